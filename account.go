@@ -34,7 +34,7 @@ type (
 				Contacts  map[string]*CustomFieldInfo `json:"contacts" validate:"omitempty,dive,required"`
 				Leads     map[string]*CustomFieldInfo `json:"leads,omitempty" validate:"omitempty,dive,required"`
 				Companies map[string]*CustomFieldInfo `json:"companies,omitempty" validate:"omitempty,dive,required"`
-				Customers []interface{}               `json:"customers,omitempty" validate:"omitempty,dive,required"`
+				Customers map[string]*CustomFieldInfo `json:"customers,omitempty" validate:"omitempty,dive,required"`
 			} `json:"custom_fields" validate:"omitempty"`
 			NoteTypes map[string]*NoteType `json:"note_types" validate:"omitempty,dive,required"`
 			Groups    map[string]*Group    `json:"groups" validate:"omitempty,dive,required"`
@@ -93,6 +93,12 @@ func (c *Client) GetAccount(ctx context.Context, reqParams *AccountRequestParams
 		return nil, err
 	}
 
+	c.mu.Lock()
+	if c.timezone == "" {
+		c.timezone = accountResponse.Timezone
+	}
+	c.mu.Unlock()
+
 	return accountResponse, nil
 }
 
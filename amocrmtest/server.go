@@ -0,0 +1,434 @@
+// Package amocrmtest provides an in-process fake amoCRM server for hermetic tests.
+//
+// The server emulates API v2 authorization, leads, contacts, notes, tasks, pipelines and account endpoints
+// with an in-memory store and reproduces quirks of the real API: empty arrays serialized as empty objects,
+// errors reported as response.error payloads and 204 No Content for empty lists.
+package amocrmtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+type (
+	Option func(s *Server)
+
+	// Server is a fake amoCRM account, it is safe for concurrent use
+	Server struct {
+		*httptest.Server
+
+		Subdomain string
+		Login     string
+		APIHash   string
+
+		accessToken string
+		rateLimit   int
+		window      time.Time
+		windowCount int
+		sessions    map[string]bool
+		failures    []*Failure
+		requests    []*Request
+		store       *store
+		mu          sync.Mutex
+	}
+
+	// Failure replaces the response for matching requests, empty Method or Path match any request
+	Failure struct {
+		Method     string
+		Path       string
+		StatusCode int
+		Header     http.Header
+		Body       string
+		// Times is the number of requests to fail, zero fails only the next one
+		Times int
+	}
+
+	Request struct {
+		Method string
+		Path   string
+		Query  url.Values
+		Header http.Header
+		Body   []byte
+	}
+)
+
+const (
+	DefaultSubdomain = "test"
+	DefaultLogin     = "test@example.com"
+	DefaultAPIHash   = "0123456789abcdef"
+	DefaultUserID    = 1
+	DefaultAccountID = 1
+
+	sessionCookieName = "session_id"
+	authPath          = "/private/api/auth.php"
+)
+
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		Subdomain: DefaultSubdomain,
+		Login:     DefaultLogin,
+		APIHash:   DefaultAPIHash,
+		sessions:  make(map[string]bool),
+		store:     newStore(),
+	}
+
+	for _, o := range opts {
+		o(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+func WithCredentials(login, apiHash string) Option {
+	return func(s *Server) {
+		s.Login = login
+		s.APIHash = apiHash
+	}
+}
+
+// WithAccessToken makes the server accept OAuth 2.0 Bearer authorization with the given access token
+func WithAccessToken(token string) Option {
+	return func(s *Server) {
+		s.accessToken = token
+	}
+}
+
+// WithRateLimit makes the server respond with 429 Too Many Requests when more than rps requests are made within a second
+func WithRateLimit(rps int) Option {
+	return func(s *Server) {
+		s.rateLimit = rps
+	}
+}
+
+// Client creates amoCRM client authorized with the server credentials
+func (s *Server) Client(opts ...amocrm.ClientOption) (*amocrm.Client, error) {
+	return amocrm.NewClient(s.URL, s.Login, s.APIHash, opts...)
+}
+
+func (s *Server) SetRateLimit(rps int) {
+	s.mu.Lock()
+	s.rateLimit = rps
+	s.mu.Unlock()
+}
+
+func (s *Server) Fail(f *Failure) {
+	s.mu.Lock()
+	s.failures = append(s.failures, f)
+	s.mu.Unlock()
+}
+
+// ExpireSessions invalidates all sessions, so the next request is responded with 401 Unauthorized
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	s.sessions = make(map[string]bool)
+	s.mu.Unlock()
+}
+
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]*Request, len(s.requests))
+	copy(requests, s.requests)
+
+	return requests
+}
+
+// ResponseError builds response.error payload, amoCRM uses to report errors even with 200 OK status
+func ResponseError(code int, detail string) string {
+	body, _ := json.Marshal(map[string]interface{}{
+		"response": map[string]string{
+			"error":      detail,
+			"error_code": strconv.Itoa(code),
+		},
+	})
+
+	return string(body)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, amocrm.InvalidRequestCode, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, &Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	if s.rateLimited() {
+		s.mu.Unlock()
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, amocrm.RateLimitExceededCode, "Too many requests")
+		return
+	}
+
+	if f := s.failure(r); f != nil {
+		s.mu.Unlock()
+		for k, v := range f.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(f.StatusCode)
+		_, _ = w.Write([]byte(f.Body))
+		return
+	}
+
+	if r.URL.Path == authPath {
+		s.mu.Unlock()
+		s.authorize(w, r, body)
+		return
+	}
+
+	authorized := s.authorized(r)
+	s.mu.Unlock()
+
+	if !authorized {
+		writeError(w, http.StatusUnauthorized, amocrm.InvalidCredentialsCode, "Authorization failed")
+		return
+	}
+
+	s.store.serve(w, r, body)
+}
+
+// rateLimited must be called with s.mu locked
+func (s *Server) rateLimited() bool {
+	if s.rateLimit <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	if now.Sub(s.window) >= time.Second {
+		s.window = now
+		s.windowCount = 0
+	}
+
+	s.windowCount++
+
+	return s.windowCount > s.rateLimit
+}
+
+// failure must be called with s.mu locked
+func (s *Server) failure(r *http.Request) *Failure {
+	for i, f := range s.failures {
+		if (f.Method != "" && f.Method != r.Method) || (f.Path != "" && f.Path != r.URL.Path) {
+			continue
+		}
+
+		if f.Times > 1 {
+			f.Times--
+		} else {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+// authorized must be called with s.mu locked
+func (s *Server) authorized(r *http.Request) bool {
+	if s.accessToken != "" && r.Header.Get("Authorization") == "Bearer "+s.accessToken {
+		return true
+	}
+
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return false
+	}
+
+	return s.sessions[cookie.Value]
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, body []byte) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil || r.Method != http.MethodPost {
+		writeError(w, http.StatusBadRequest, amocrm.InvalidRequestCode, "Invalid request")
+		return
+	}
+
+	if values.Get("USER_LOGIN") != s.Login || values.Get("USER_HASH") != s.APIHash {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": map[string]interface{}{
+				"auth":       false,
+				"error":      "Wrong login or password",
+				"error_code": strconv.Itoa(amocrm.InvalidCredentialsCode),
+			},
+		})
+		return
+	}
+
+	s.mu.Lock()
+	session := fmt.Sprintf("%x", time.Now().UnixNano())
+	s.sessions[session] = true
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: session, Path: "/"})
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"response": map[string]interface{}{
+			"auth": true,
+			"accounts": []map[string]interface{}{{
+				"id":        DefaultAccountID,
+				"name":      s.Subdomain,
+				"subdomain": s.Subdomain,
+				"language":  "ru",
+				"timezone":  s.store.timezone,
+			}},
+			"user": map[string]interface{}{
+				"id":       DefaultUserID,
+				"language": "ru",
+			},
+			"server_time": time.Now().Unix(),
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code int, detail string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(ResponseError(code, detail)))
+}
+
+func parseIDs(value string) []int {
+	if value == "" {
+		return nil
+	}
+
+	var ids []int
+	for _, s := range strings.Split(value, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Lead returns a copy of the stored lead with its links, nil if the lead does not exist
+func (s *Server) Lead(id int) *amocrm.Lead {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	lead, ok := s.store.leads[id]
+	if !ok {
+		return nil
+	}
+
+	return s.store.renderLead(lead)
+}
+
+func (s *Server) Contact(id int) *amocrm.Contact {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	contact, ok := s.store.contacts[id]
+	if !ok {
+		return nil
+	}
+
+	return s.store.renderContact(contact)
+}
+
+func (s *Server) Note(id int) *amocrm.Note {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	note, ok := s.store.notes[id]
+	if !ok {
+		return nil
+	}
+
+	out := *note
+	return &out
+}
+
+// AddNote stores a copy of the note bypassing validation, e.g. to seed notes created by amoCRM or telephony,
+// missing id, account, author, timestamps and links are filled in. The id of the note is returned.
+func (s *Server) AddNote(note *amocrm.Note) int {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	stored := *note
+	if stored.ID == 0 {
+		stored.ID = s.store.id()
+	}
+
+	now := amocrm.NewTimestamp(time.Now())
+	stored.AccountID = orDefault(stored.AccountID, DefaultAccountID)
+	stored.CreatedBy = orDefault(stored.CreatedBy, DefaultUserID)
+	stored.ResponsibleUserID = orDefault(stored.ResponsibleUserID, DefaultUserID)
+	stored.CreatedAt = orNow(stored.CreatedAt, now)
+	stored.UpdatedAt = orNow(stored.UpdatedAt, now)
+	if stored.Links == nil {
+		stored.Links = links(notesPath + "?id=" + strconv.Itoa(stored.ID))
+	}
+
+	s.store.notes[stored.ID] = &stored
+
+	return stored.ID
+}
+
+func (s *Server) Task(id int) *amocrm.Task {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	task, ok := s.store.tasks[id]
+	if !ok {
+		return nil
+	}
+
+	out := *task
+	return &out
+}
+
+func (s *Server) AddPipeline(pipeline *amocrm.Pipeline) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if pipeline.Links == nil {
+		pipeline.Links = links(pipelinesPath + "?id=" + strconv.Itoa(pipeline.ID))
+	}
+	s.store.pipelines[pipeline.ID] = pipeline
+}
+
+func (s *Server) AddUser(user *amocrm.User) {
+	s.store.mu.Lock()
+	s.store.users[user.ID] = user
+	s.store.mu.Unlock()
+}
+
+func (s *Server) AddCustomField(entity amocrm.EntityType, field *amocrm.CustomFieldInfo) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if s.store.customFields[entity] == nil {
+		s.store.customFields[entity] = make(map[int]*amocrm.CustomFieldInfo)
+	}
+	s.store.customFields[entity][field.ID] = field
+}
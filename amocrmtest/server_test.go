@@ -0,0 +1,101 @@
+package amocrmtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+func newTestClient(t *testing.T, s *Server) *amocrm.Client {
+	t.Helper()
+
+	c, err := s.Client(amocrm.WithRateLimit(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Authorize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func addTestLead(t *testing.T, c *amocrm.Client, name string) int {
+	t.Helper()
+
+	id, err := c.AddLead(context.Background(), &amocrm.LeadAdd{Name: name, StatusID: 10, PipelineID: DefaultPipelineID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+func TestUpdateTaskKeepsCompletion(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := newTestClient(t, s)
+	ctx := context.Background()
+	leadID := addTestLead(t, c, "lead")
+
+	id, err := c.AddTask(ctx, &amocrm.TaskAdd{ElementID: leadID, ElementType: amocrm.LeadTaskElementType, TaskType: 1, Text: "call", CompleteTill: amocrm.NewTimestamp(time.Now().Add(time.Hour))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ModifyTask(ctx, id, func(task *amocrm.Task, update *amocrm.TaskUpdate) error {
+		update.IsCompleted = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ModifyTask(ctx, id, func(task *amocrm.Task, update *amocrm.TaskUpdate) error {
+		update.Text = "call again"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if task := s.Task(id); !task.IsCompleted || task.Text != "call again" {
+		t.Fatalf("unexpected task: %+v", task)
+	}
+}
+
+func TestAddNote(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := newTestClient(t, s)
+	leadID := addTestLead(t, c, "lead")
+
+	id := s.AddNote(&amocrm.Note{
+		ElementID:     leadID,
+		ElementType:   amocrm.LeadNoteElementType,
+		NoteType:      amocrm.IncomingCallNoteKind,
+		Text:          "missed call",
+		RawParameters: []byte(`{"PHONE":"+79001234567","DURATION":0,"call_status":6}`),
+	})
+
+	notes, err := c.GetNotes(context.Background(), &amocrm.NoteRequestParams{Type: amocrm.LeadNoteType, ID: []int{id}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(notes) != 1 || notes[0].ElementID != leadID || notes[0].CreatedAt.IsZero() || notes[0].Links == nil {
+		t.Fatalf("unexpected notes: %+v", notes)
+	}
+
+	call, err := notes[0].Params()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params, ok := call.(*amocrm.NotePostParameters); !ok || params.CallStatus != amocrm.NoAnswerCallStatus {
+		t.Fatalf("unexpected params: %+v", call)
+	}
+}
@@ -0,0 +1,1094 @@
+package amocrmtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+type (
+	store struct {
+		timezone     string
+		nextID       int
+		leads        map[int]*amocrm.Lead
+		contacts     map[int]*amocrm.Contact
+		notes        map[int]*amocrm.Note
+		tasks        map[int]*amocrm.Task
+		pipelines    map[int]*amocrm.Pipeline
+		users        map[int]*amocrm.User
+		tags         map[string]int
+		customFields map[amocrm.EntityType]map[int]*amocrm.CustomFieldInfo
+		taskTypes    map[int]*amocrm.TaskType
+		noteTypes    map[int]*amocrm.NoteType
+		leadContacts map[int]map[int]bool
+		mu           sync.Mutex
+	}
+
+	postRequest struct {
+		Add    []json.RawMessage `json:"add"`
+		Update []json.RawMessage `json:"update"`
+	}
+
+	postResult struct {
+		items  []map[string]interface{}
+		errors map[string]map[string]string
+	}
+)
+
+const (
+	leadsPath     = "/api/v2/leads"
+	contactsPath  = "/api/v2/contacts"
+	notesPath     = "/api/v2/notes"
+	tasksPath     = "/api/v2/tasks"
+	pipelinesPath = "/api/v2/pipelines"
+	accountPath   = "/api/v2/account"
+
+	DefaultPipelineID = 1
+	WonStatusID       = 142
+	LostStatusID      = 143
+
+	PhoneCustomFieldID = 1
+	EmailCustomFieldID = 2
+
+	// amoCRM rejects updates with updated_at older than the stored one
+	staleUpdateError = "Last modified date is older than in database"
+	notFoundError    = "Entity not found"
+
+	defaultPageSize = 500
+)
+
+var (
+	noteElementTypes = map[string]int{
+		string(amocrm.ContactNoteType): int(amocrm.ContactTaskElementType),
+		string(amocrm.LeadNoteType):    int(amocrm.LeadTaskElementType),
+		string(amocrm.CompanyNoteType): int(amocrm.CompanyTaskElementType),
+		string(amocrm.TaskNoteType):    4,
+	}
+
+	taskElementTypes = map[string]amocrm.TaskElementType{
+		string(amocrm.ContactTaskType):  amocrm.ContactTaskElementType,
+		string(amocrm.LeadTaskType):     amocrm.LeadTaskElementType,
+		string(amocrm.CompanyTaskType):  amocrm.CompanyTaskElementType,
+		string(amocrm.CustomerTaskType): amocrm.CustomerTaskElementType,
+	}
+)
+
+func newStore() *store {
+	statuses := map[string]*amocrm.PipelineStatus{
+		"10":                       {ID: 10, Name: "Incoming", Color: "#99ccff", Sort: 10, IsEditable: true},
+		"20":                       {ID: 20, Name: "In progress", Color: "#ffff99", Sort: 20, IsEditable: true},
+		strconv.Itoa(WonStatusID):  {ID: WonStatusID, Name: "Closed - won", Color: "#CCFF66", Sort: 10000},
+		strconv.Itoa(LostStatusID): {ID: LostStatusID, Name: "Closed - lost", Color: "#D5D8DB", Sort: 11000},
+	}
+
+	user := &amocrm.User{ID: DefaultUserID, Name: "Test", Login: DefaultLogin, Language: "ru", IsActive: true, IsAdmin: true}
+	rights := &user.Rights
+	for _, right := range []*string{
+		&rights.Mail, &rights.IncomingLeads, &rights.Catalogs,
+		&rights.LeadAdd, &rights.LeadView, &rights.LeadEdit, &rights.LeadDelete, &rights.LeadExport,
+		&rights.ContactAdd, &rights.ContactView, &rights.ContactEdit, &rights.ContactDelete, &rights.ContactExport,
+		&rights.CompanyAdd, &rights.CompanyView, &rights.CompanyEdit, &rights.CompanyDelete, &rights.CompanyExport,
+		&rights.TaskEdit, &rights.TaskDelete,
+	} {
+		*right = "A"
+	}
+
+	return &store{
+		timezone: "Europe/Moscow",
+		nextID:   1000,
+		leads:    make(map[int]*amocrm.Lead),
+		contacts: make(map[int]*amocrm.Contact),
+		notes:    make(map[int]*amocrm.Note),
+		tasks:    make(map[int]*amocrm.Task),
+		pipelines: map[int]*amocrm.Pipeline{
+			DefaultPipelineID: {ID: DefaultPipelineID, Name: "Sales", Sort: 1, IsMain: true, Statuses: statuses, Links: links(pipelinesPath + "?id=1")},
+		},
+		users: map[int]*amocrm.User{DefaultUserID: user},
+		tags:  make(map[string]int),
+		customFields: map[amocrm.EntityType]map[int]*amocrm.CustomFieldInfo{
+			amocrm.ContactEntityType: {
+				PhoneCustomFieldID: {
+					ID: PhoneCustomFieldID, Name: "Phone", Code: "PHONE", FieldType: amocrm.MultiTextCustomFieldType, Sort: 4,
+					IsMultiple: true, IsSystem: true, IsEditable: true, IsVisible: true,
+					Enums: map[string]string{"1": "WORK", "2": "WORKDD", "3": "MOB", "4": "FAX", "5": "HOME", "6": "OTHER"},
+				},
+				EmailCustomFieldID: {
+					ID: EmailCustomFieldID, Name: "Email", Code: "EMAIL", FieldType: amocrm.MultiTextCustomFieldType, Sort: 6,
+					IsMultiple: true, IsSystem: true, IsEditable: true, IsVisible: true,
+					Enums: map[string]string{"7": "WORK", "8": "PRIV", "9": "OTHER"},
+				},
+			},
+			amocrm.LeadEntityType: {},
+		},
+		taskTypes: map[int]*amocrm.TaskType{
+			1: {ID: 1, Name: "Follow-up"},
+			2: {ID: 2, Name: "Meeting"},
+		},
+		noteTypes: map[int]*amocrm.NoteType{
+			1:   {ID: 1, Code: "DEAL_CREATED"},
+			3:   {ID: 3, Code: "DEAL_STATUS_CHANGED"},
+			4:   {ID: 4, Code: "COMMON", IsEditable: true},
+			5:   {ID: 5, Code: "ATTACHMENT"},
+			10:  {ID: 10, Code: "CALL_IN"},
+			11:  {ID: 11, Code: "CALL_OUT"},
+			25:  {ID: 25, Code: "SYSTEM"},
+			26:  {ID: 26, Code: "GEOLOCATION"},
+			27:  {ID: 27, Code: "INVOICE_PAID"},
+			102: {ID: 102, Code: "SMS_IN"},
+			103: {ID: 103, Code: "SMS_OUT"},
+		},
+		leadContacts: make(map[int]map[int]bool),
+	}
+}
+
+func (st *store) serve(w http.ResponseWriter, r *http.Request, body []byte) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	switch {
+	case r.URL.Path == accountPath && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, st.account(r))
+	case r.URL.Path == pipelinesPath && r.Method == http.MethodGet:
+		st.listPipelines(w, r)
+	case r.Method == http.MethodGet && (r.URL.Path == leadsPath || r.URL.Path == contactsPath || r.URL.Path == notesPath || r.URL.Path == tasksPath):
+		st.list(w, r)
+	case r.Method == http.MethodPost && (r.URL.Path == leadsPath || r.URL.Path == contactsPath || r.URL.Path == notesPath || r.URL.Path == tasksPath):
+		st.post(w, r, body)
+	default:
+		writeError(w, http.StatusNotFound, amocrm.InvalidRequestCode, "Not found")
+	}
+}
+
+func (st *store) id() int {
+	st.nextID++
+	return st.nextID
+}
+
+func (st *store) account(r *http.Request) map[string]interface{} {
+	embedded := make(map[string]interface{})
+	for _, with := range strings.Split(r.URL.Query().Get("with"), ",") {
+		switch amocrm.AccountWithType(with) {
+		case amocrm.AccountWithUsers:
+			embedded["users"] = keyed(st.users)
+		case amocrm.AccountWithPipelines:
+			embedded["pipelines"] = keyed(st.pipelines)
+		case amocrm.AccountWithCustomFields:
+			customFields := make(map[string]interface{})
+			for entity, fields := range st.customFields {
+				customFields[string(entity)] = keyed(fields)
+			}
+			embedded["custom_fields"] = customFields
+		case amocrm.AccountWithTaskTypes:
+			embedded["task_types"] = keyed(st.taskTypes)
+		case amocrm.AccountWithNoteTypes:
+			embedded["note_types"] = keyed(st.noteTypes)
+		case amocrm.AccountWithGroups:
+			embedded["groups"] = map[string]*amocrm.Group{"1": {ID: 1, Name: "Main"}}
+		}
+	}
+
+	return map[string]interface{}{
+		"id":              DefaultAccountID,
+		"name":            "Test",
+		"subdomain":       DefaultSubdomain,
+		"currency":        "RUB",
+		"timezone":        st.timezone,
+		"timezone_offset": "+03:00",
+		"language":        "ru",
+		"date_pattern": map[string]string{
+			"date":      "d.m.Y",
+			"time":      "H:i",
+			"date_time": "d.m.Y H:i",
+			"time_full": "H:i:s",
+		},
+		"current_user": DefaultUserID,
+		"_embedded":    embedded,
+	}
+}
+
+func (st *store) listPipelines(w http.ResponseWriter, r *http.Request) {
+	items := make(map[string]*amocrm.Pipeline)
+	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+	for _, p := range st.pipelines {
+		if id == 0 || p.ID == id {
+			items[strconv.Itoa(p.ID)] = p
+		}
+	}
+
+	if len(items) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_links":    links(r.URL.RequestURI()),
+		"_embedded": map[string]interface{}{"items": items},
+	})
+}
+
+func (st *store) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var items []interface{}
+	switch r.URL.Path {
+	case leadsPath:
+		for _, id := range sortedIDs(st.leads) {
+			if lead := st.leads[id]; st.matchLead(lead, q) && matchModified(r, lead.UpdatedAt) {
+				items = append(items, withEmptyObjects(st.renderLead(lead), "tags", "custom_fields"))
+			}
+		}
+	case contactsPath:
+		for _, id := range sortedIDs(st.contacts) {
+			if contact := st.contacts[id]; st.matchContact(contact, q) && matchModified(r, contact.UpdatedAt) {
+				items = append(items, withEmptyObjects(st.renderContact(contact), "tags", "custom_fields"))
+			}
+		}
+	case notesPath:
+		elementType, ok := noteElementTypes[q.Get("type")]
+		if !ok {
+			writeError(w, http.StatusBadRequest, amocrm.InvalidRequestParametersCode, "Invalid type")
+			return
+		}
+		for _, id := range sortedIDs(st.notes) {
+			if note := st.notes[id]; note.ElementType == elementType && matchNote(note, q) && matchModified(r, note.UpdatedAt) {
+				items = append(items, note)
+			}
+		}
+	case tasksPath:
+		for _, id := range sortedIDs(st.tasks) {
+			if task := st.tasks[id]; matchTask(task, q) && matchModified(r, task.UpdatedAt) {
+				items = append(items, task)
+			}
+		}
+	}
+
+	items = page(items, q)
+
+	// amoCRM responds with 204 No Content instead of an empty list
+	if len(items) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_links":    links(r.URL.RequestURI()),
+		"_embedded": map[string]interface{}{"items": items},
+	})
+}
+
+func (st *store) post(w http.ResponseWriter, r *http.Request, body []byte) {
+	req := new(postRequest)
+	if err := json.Unmarshal(body, req); err != nil {
+		writeError(w, http.StatusBadRequest, amocrm.BodyMustBeJSONCode, "Body must be JSON")
+		return
+	}
+
+	if len(req.Add) == 0 && len(req.Update) == 0 {
+		writeError(w, http.StatusOK, amocrm.LeadEmptyRequestCode, "Empty request")
+		return
+	}
+
+	result := &postResult{errors: make(map[string]map[string]string)}
+	for _, raw := range req.Add {
+		switch r.URL.Path {
+		case leadsPath:
+			st.addLead(raw, result)
+		case contactsPath:
+			st.addContact(raw, result)
+		case notesPath:
+			st.addNote(raw, result)
+		case tasksPath:
+			st.addTask(raw, result)
+		}
+	}
+
+	for _, raw := range req.Update {
+		switch r.URL.Path {
+		case leadsPath:
+			st.updateLead(raw, result)
+		case contactsPath:
+			st.updateContact(raw, result)
+		case notesPath:
+			st.updateNote(raw, result)
+		case tasksPath:
+			st.updateTask(raw, result)
+		}
+	}
+
+	for i, item := range result.items {
+		item["_links"] = links(r.URL.Path + "?id=" + strconv.Itoa(item["id"].(int)))
+		result.items[i] = item
+	}
+
+	embedded := map[string]interface{}{"items": result.items}
+	if len(result.errors) > 0 {
+		embedded["errors"] = result.errors
+	} else {
+		// empty errors are serialized as an empty array
+		embedded["errors"] = []interface{}{}
+	}
+
+	if result.items == nil {
+		embedded["items"] = []interface{}{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_links":    links(r.URL.Path),
+		"_embedded": embedded,
+	})
+}
+
+func (res *postResult) ok(id, requestID int) {
+	item := map[string]interface{}{"id": id}
+	if requestID != 0 {
+		item["request_id"] = requestID
+	}
+
+	res.items = append(res.items, item)
+}
+
+func (res *postResult) fail(action string, key int, msg string) {
+	if res.errors[action] == nil {
+		res.errors[action] = make(map[string]string)
+	}
+
+	res.errors[action][strconv.Itoa(key)] = msg
+}
+
+func (st *store) addLead(raw json.RawMessage, res *postResult) {
+	add := new(amocrm.LeadAdd)
+	if err := json.Unmarshal(raw, add); err != nil {
+		res.fail("add", 0, err.Error())
+		return
+	}
+
+	now := amocrm.NewTimestamp(time.Now())
+	lead := &amocrm.Lead{
+		ID:                st.id(),
+		Name:              add.Name,
+		ResponsibleUserID: orDefault(add.ResponsibleUserID, DefaultUserID),
+		CreatedBy:         DefaultUserID,
+		CreatedAt:         orNow(add.CreatedAt, now),
+		UpdatedAt:         orNow(add.UpdatedAt, now),
+		AccountID:         DefaultAccountID,
+		StatusID:          add.StatusID,
+		Sale:              add.Sale,
+		Tags:              st.parseTags(add.Tags),
+		CustomFields:      st.applyCustomFields(amocrm.LeadEntityType, nil, add.CustomFields),
+	}
+	lead.Pipeline.ID = orDefault(add.PipelineID, DefaultPipelineID)
+	lead.Company.ID = add.CompanyID
+	st.closeLead(lead)
+
+	st.leads[lead.ID] = lead
+	for _, contactID := range add.ContactsID {
+		if id, err := strconv.Atoi(contactID); err == nil {
+			st.link(lead.ID, id)
+		}
+	}
+
+	res.ok(lead.ID, add.RequestID)
+}
+
+func (st *store) updateLead(raw json.RawMessage, res *postResult) {
+	update := new(amocrm.LeadUpdate)
+	if err := json.Unmarshal(raw, update); err != nil {
+		res.fail("update", 0, err.Error())
+		return
+	}
+
+	lead, ok := st.leads[update.ID]
+	if !ok {
+		res.fail("update", update.ID, notFoundError)
+		return
+	}
+
+	if update.UpdatedAt < lead.UpdatedAt {
+		res.fail("update", update.ID, staleUpdateError)
+		return
+	}
+
+	lead.UpdatedAt = update.UpdatedAt
+	if update.Name != "" {
+		lead.Name = update.Name
+	}
+	if update.StatusID != 0 {
+		lead.StatusID = update.StatusID
+	}
+	if update.PipelineID != 0 {
+		lead.Pipeline.ID = update.PipelineID
+	}
+	if update.ResponsibleUserID != 0 {
+		lead.ResponsibleUserID = update.ResponsibleUserID
+	}
+	if update.Sale != 0 {
+		lead.Sale = update.Sale
+	}
+	if update.CompanyID != 0 {
+		lead.Company.ID = update.CompanyID
+	}
+	if update.Tags != "" {
+		lead.Tags = st.parseTags(update.Tags)
+	}
+	lead.CustomFields = st.applyCustomFields(amocrm.LeadEntityType, lead.CustomFields, update.CustomFields)
+	st.closeLead(lead)
+
+	for _, contactID := range update.ContactsID {
+		if id, err := strconv.Atoi(contactID); err == nil {
+			st.link(lead.ID, id)
+		}
+	}
+	if update.Unlink != nil {
+		for _, contactID := range update.Unlink.ContactsID {
+			st.unlink(lead.ID, contactID)
+		}
+		if update.Unlink.CompanyID != 0 && update.Unlink.CompanyID == lead.Company.ID {
+			lead.Company.ID = 0
+		}
+	}
+
+	res.ok(lead.ID, update.RequestID)
+}
+
+func (st *store) addContact(raw json.RawMessage, res *postResult) {
+	add := new(amocrm.ContactAdd)
+	if err := json.Unmarshal(raw, add); err != nil {
+		res.fail("add", 0, err.Error())
+		return
+	}
+
+	now := amocrm.NewTimestamp(time.Now())
+	contact := &amocrm.Contact{
+		ID:                st.id(),
+		Name:              add.Name,
+		ResponsibleUserID: orDefault(add.ResponsibleUserID, DefaultUserID),
+		CreatedBy:         orDefault(add.CreatedBy, DefaultUserID),
+		CreatedAt:         orNow(add.CreatedAt, now),
+		UpdatedAt:         orNow(add.UpdatedAt, now),
+		AccountID:         DefaultAccountID,
+		UpdatedBy:         DefaultUserID,
+		Tags:              st.parseTags(add.Tags),
+		CustomFields:      st.applyCustomFields(amocrm.ContactEntityType, nil, add.CustomFields),
+	}
+	contact.Company.ID = add.CompanyID
+	contact.Company.Name = add.CompanyName
+
+	st.contacts[contact.ID] = contact
+	for _, leadID := range add.LeadsID {
+		if id, err := strconv.Atoi(leadID); err == nil {
+			st.link(id, contact.ID)
+		}
+	}
+
+	res.ok(contact.ID, add.RequestID)
+}
+
+func (st *store) updateContact(raw json.RawMessage, res *postResult) {
+	update := new(amocrm.ContactUpdate)
+	if err := json.Unmarshal(raw, update); err != nil {
+		res.fail("update", 0, err.Error())
+		return
+	}
+
+	contact, ok := st.contacts[update.ID]
+	if !ok {
+		res.fail("update", update.ID, notFoundError)
+		return
+	}
+
+	if update.UpdatedAt < contact.UpdatedAt {
+		res.fail("update", update.ID, staleUpdateError)
+		return
+	}
+
+	contact.UpdatedAt = update.UpdatedAt
+	if update.Name != "" {
+		contact.Name = update.Name
+	}
+	if update.ResponsibleUserID != 0 {
+		contact.ResponsibleUserID = update.ResponsibleUserID
+	}
+	if update.CompanyID != 0 {
+		contact.Company.ID = update.CompanyID
+	}
+	if update.CompanyName != "" {
+		contact.Company.Name = update.CompanyName
+	}
+	if update.Tags != "" {
+		contact.Tags = st.parseTags(update.Tags)
+	}
+	contact.CustomFields = st.applyCustomFields(amocrm.ContactEntityType, contact.CustomFields, update.CustomFields)
+
+	for _, leadID := range update.LeadsID {
+		if id, err := strconv.Atoi(leadID); err == nil {
+			st.link(id, contact.ID)
+		}
+	}
+	if update.Unlink != nil {
+		for _, leadID := range update.Unlink.LeadsID {
+			st.unlink(leadID, contact.ID)
+		}
+		if update.Unlink.CompanyID != 0 && update.Unlink.CompanyID == contact.Company.ID {
+			contact.Company.ID = 0
+			contact.Company.Name = ""
+		}
+	}
+
+	res.ok(contact.ID, 0)
+}
+
+func (st *store) addNote(raw json.RawMessage, res *postResult) {
+	// params are kept as is, since their type depends on the note type
+	add := new(struct {
+		amocrm.NoteAdd
+		Params json.RawMessage `json:"params"`
+	})
+	if err := json.Unmarshal(raw, add); err != nil {
+		res.fail("add", 0, err.Error())
+		return
+	}
+
+	now := amocrm.NewTimestamp(time.Now())
+	note := &amocrm.Note{
+		ID:                st.id(),
+		CreatedBy:         orDefault(add.CreatedBy, DefaultUserID),
+		AccountID:         DefaultAccountID,
+		IsEditable:        true,
+		ElementID:         add.ElementID,
+		ElementType:       add.ElementType,
+		Text:              add.Text,
+		NoteType:          amocrm.NoteKind(orDefault(int(add.NoteType), int(amocrm.CommonNoteKind))),
+		CreatedAt:         orNow(add.CreatedAt, now),
+		UpdatedAt:         orNow(add.UpdatedAt, now),
+		ResponsibleUserID: orDefault(add.ResponsibleUserID, DefaultUserID),
+	}
+	if len(add.Params) > 0 && add.Params[0] == '{' {
+		note.RawParameters = add.Params
+
+		var params struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(add.Params, &params); err == nil && note.Text == "" {
+			note.Text = params.Text
+		}
+	}
+	note.Links = links(notesPath + "?id=" + strconv.Itoa(note.ID))
+
+	st.notes[note.ID] = note
+	res.ok(note.ID, add.RequestID)
+}
+
+func (st *store) updateNote(raw json.RawMessage, res *postResult) {
+	var update struct {
+		ID        int              `json:"id,string"`
+		Text      string           `json:"text"`
+		UpdatedAt amocrm.Timestamp `json:"updated_at"`
+	}
+	if err := json.Unmarshal(raw, &update); err != nil {
+		res.fail("update", 0, err.Error())
+		return
+	}
+
+	note, ok := st.notes[update.ID]
+	if !ok {
+		res.fail("update", update.ID, notFoundError)
+		return
+	}
+
+	if update.UpdatedAt < note.UpdatedAt {
+		res.fail("update", update.ID, staleUpdateError)
+		return
+	}
+
+	note.UpdatedAt = update.UpdatedAt
+	if update.Text != "" {
+		note.Text = update.Text
+	}
+
+	res.ok(note.ID, 0)
+}
+
+func (st *store) addTask(raw json.RawMessage, res *postResult) {
+	add := new(amocrm.TaskAdd)
+	if err := json.Unmarshal(raw, add); err != nil {
+		res.fail("add", 0, err.Error())
+		return
+	}
+
+	now := amocrm.NewTimestamp(time.Now())
+	task := &amocrm.Task{
+		ID:                st.id(),
+		ElementID:         add.ElementID,
+		ElementType:       add.ElementType,
+		CompleteTillAt:    orNow(add.CompleteTill, now),
+		TaskType:          add.TaskType,
+		Text:              add.Text,
+		CreatedAt:         orNow(add.CreatedAt, now),
+		UpdatedAt:         orNow(add.UpdatedAt, now),
+		ResponsibleUserID: orDefault(add.ResponsibleUserID, DefaultUserID),
+		IsCompleted:       add.IsCompleted,
+		CreatedBy:         orDefault(add.CreatedBy, DefaultUserID),
+		AccountID:         DefaultAccountID,
+	}
+	task.Links = links(tasksPath + "?id=" + strconv.Itoa(task.ID))
+
+	st.tasks[task.ID] = task
+	res.ok(task.ID, add.RequestID)
+}
+
+func (st *store) updateTask(raw json.RawMessage, res *postResult) {
+	update := new(amocrm.TaskUpdate)
+	if err := json.Unmarshal(raw, update); err != nil {
+		res.fail("update", 0, err.Error())
+		return
+	}
+
+	task, ok := st.tasks[update.ID]
+	if !ok {
+		res.fail("update", update.ID, notFoundError)
+		return
+	}
+
+	if update.UpdatedAt < task.UpdatedAt {
+		res.fail("update", update.ID, staleUpdateError)
+		return
+	}
+
+	task.UpdatedAt = update.UpdatedAt
+	if update.ElementID != 0 {
+		task.ElementID = update.ElementID
+	}
+	if update.ElementType != 0 {
+		task.ElementType = update.ElementType
+	}
+	if update.CompleteTill != 0 {
+		task.CompleteTillAt = update.CompleteTill
+	}
+	if update.TaskType != 0 {
+		task.TaskType = update.TaskType
+	}
+	if update.Text != "" {
+		task.Text = update.Text
+	}
+	if update.ResponsibleUserID != 0 {
+		task.ResponsibleUserID = update.ResponsibleUserID
+	}
+	// is_completed is omitted when false, so the task is reopened only when the field is sent explicitly
+	var fields struct {
+		IsCompleted *bool `json:"is_completed"`
+	}
+	if err := json.Unmarshal(raw, &fields); err == nil && fields.IsCompleted != nil {
+		task.IsCompleted = *fields.IsCompleted
+	}
+
+	res.ok(task.ID, update.RequestID)
+}
+
+func (st *store) closeLead(lead *amocrm.Lead) {
+	if lead.StatusID == WonStatusID || lead.StatusID == LostStatusID {
+		if lead.ClosedAt == 0 {
+			lead.ClosedAt = lead.UpdatedAt
+		}
+	} else {
+		lead.ClosedAt = 0
+	}
+}
+
+func (st *store) link(leadID, contactID int) {
+	if _, ok := st.leads[leadID]; !ok {
+		return
+	}
+	if _, ok := st.contacts[contactID]; !ok {
+		return
+	}
+
+	if st.leadContacts[leadID] == nil {
+		st.leadContacts[leadID] = make(map[int]bool)
+	}
+	st.leadContacts[leadID][contactID] = true
+}
+
+func (st *store) unlink(leadID, contactID int) {
+	delete(st.leadContacts[leadID], contactID)
+}
+
+func (st *store) renderLead(lead *amocrm.Lead) *amocrm.Lead {
+	out := *lead
+	out.Contact.ID = nil
+	for _, contactID := range sortedIDs(st.leadContacts[lead.ID]) {
+		out.Contact.ID = append(out.Contact.ID, contactID)
+	}
+	out.MainContact.ID = 0
+	if len(out.Contact.ID) > 0 {
+		out.MainContact.ID = out.Contact.ID[0]
+	}
+	out.Pipeline.Links = links(pipelinesPath + "?id=" + strconv.Itoa(lead.Pipeline.ID))
+	out.Links = links(leadsPath + "?id=" + strconv.Itoa(lead.ID))
+
+	return &out
+}
+
+func (st *store) renderContact(contact *amocrm.Contact) *amocrm.Contact {
+	out := *contact
+	out.Leads.ID = nil
+	for _, leadID := range sortedIDs(st.leadContacts) {
+		if st.leadContacts[leadID][contact.ID] {
+			out.Leads.ID = append(out.Leads.ID, leadID)
+		}
+	}
+	out.Links = links(contactsPath + "?id=" + strconv.Itoa(contact.ID))
+
+	return &out
+}
+
+func (st *store) parseTags(tags string) []*amocrm.Tag {
+	var result []*amocrm.Tag
+	for _, name := range strings.Split(tags, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		id, ok := st.tags[name]
+		if !ok {
+			id = st.id()
+			st.tags[name] = id
+		}
+		result = append(result, &amocrm.Tag{ID: id, Name: name})
+	}
+
+	return result
+}
+
+// applyCustomFields replaces values of the updated fields, the same way amoCRM does
+func (st *store) applyCustomFields(entity amocrm.EntityType, fields amocrm.CustomFields, updates []*amocrm.UpdateCustomField) amocrm.CustomFields {
+	for _, update := range updates {
+		info := st.customFields[entity][update.ID]
+
+		field := &amocrm.CustomField{ID: update.ID, Name: "Field " + strconv.Itoa(update.ID)}
+		if info != nil {
+			field.Name, field.Code, field.IsSystem = info.Name, info.Code, info.IsSystem
+		}
+
+		for _, v := range update.Values {
+			if value := customValue(info, v); value != nil {
+				field.Values = append(field.Values, value)
+			}
+		}
+
+		replaced := false
+		for i, f := range fields {
+			if f.ID == field.ID {
+				fields[i] = field
+				replaced = true
+			}
+		}
+		if !replaced {
+			fields = append(fields, field)
+		}
+	}
+
+	// fields without values are removed
+	result := fields[:0]
+	for _, f := range fields {
+		if len(f.Values) > 0 {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+func customValue(info *amocrm.CustomFieldInfo, v interface{}) *amocrm.CustomValue {
+	value := new(amocrm.CustomValue)
+
+	switch v := v.(type) {
+	case string:
+		// multiselect values are plain enum ids
+		value.Value = v
+		value.Enum, _ = strconv.Atoi(v)
+	case float64:
+		value.Value = strconv.FormatFloat(v, 'f', -1, 64)
+		value.Enum = int(v)
+	case map[string]interface{}:
+		switch raw := v["value"].(type) {
+		case string:
+			value.Value = raw
+		case nil:
+		default:
+			b, _ := json.Marshal(raw)
+			value.Value = string(b)
+		}
+		value.Subtype, _ = v["subtype"].(string)
+
+		if enum, ok := v["enum"].(string); ok {
+			value.Enum, _ = strconv.Atoi(enum)
+			if info != nil && value.Enum == 0 {
+				value.Enum, _ = strconv.Atoi(enumID(info.Enums, enum))
+			}
+		}
+
+		if info != nil && value.Enum == 0 {
+			switch info.FieldType {
+			case amocrm.SelectCustomFieldType, amocrm.RadioButtonCustomFieldType:
+				value.Enum, _ = strconv.Atoi(value.Value)
+			}
+		}
+	default:
+		return nil
+	}
+
+	if info != nil && value.Enum != 0 {
+		if name, ok := info.Enums[strconv.Itoa(value.Enum)]; ok && info.FieldType != amocrm.MultiTextCustomFieldType {
+			value.Value = name
+		}
+	}
+
+	if value.Value == "" {
+		return nil
+	}
+
+	return value
+}
+
+func enumID(enums map[string]string, name string) string {
+	for id, n := range enums {
+		if strings.EqualFold(n, name) {
+			return id
+		}
+	}
+
+	return ""
+}
+
+func (st *store) matchLead(lead *amocrm.Lead, q map[string][]string) bool {
+	values := url.Values(q)
+	if ids := parseIDs(values.Get("id")); ids != nil && !containsInt(ids, lead.ID) {
+		return false
+	}
+	if statuses := parseIDs(values.Get("status")); statuses != nil && !containsInt(statuses, lead.StatusID) {
+		return false
+	}
+	if values.Get("filter[active]") == "1" && (lead.StatusID == WonStatusID || lead.StatusID == LostStatusID) {
+		return false
+	}
+	if !matchResponsible(values, lead.ResponsibleUserID) || !matchDates(values, lead.CreatedAt, lead.UpdatedAt) {
+		return false
+	}
+
+	return matchQuery(values.Get("query"), lead.Name, lead.CustomFields)
+}
+
+func (st *store) matchContact(contact *amocrm.Contact, q map[string][]string) bool {
+	values := url.Values(q)
+	if ids := parseIDs(values.Get("id")); ids != nil && !containsInt(ids, contact.ID) {
+		return false
+	}
+	if !matchResponsible(values, contact.ResponsibleUserID) || !matchDates(values, contact.CreatedAt, contact.UpdatedAt) {
+		return false
+	}
+
+	return matchQuery(values.Get("query"), contact.Name, contact.CustomFields)
+}
+
+func matchNote(note *amocrm.Note, q map[string][]string) bool {
+	values := url.Values(q)
+	if ids := parseIDs(values.Get("id")); ids != nil && !containsInt(ids, note.ID) {
+		return false
+	}
+	if ids := parseIDs(values.Get("element_id")); ids != nil && !containsInt(ids, note.ElementID) {
+		return false
+	}
+	if types := parseIDs(values.Get("note_type")); types != nil && !containsInt(types, int(note.NoteType)) {
+		return false
+	}
+
+	return matchDates(values, note.CreatedAt, note.UpdatedAt)
+}
+
+func matchTask(task *amocrm.Task, q map[string][]string) bool {
+	values := url.Values(q)
+	if ids := parseIDs(values.Get("id")); ids != nil && !containsInt(ids, task.ID) {
+		return false
+	}
+	if ids := parseIDs(values.Get("element_id")); ids != nil && !containsInt(ids, task.ElementID) {
+		return false
+	}
+	if t := values.Get("type"); t != "" && taskElementTypes[t] != task.ElementType {
+		return false
+	}
+	switch values.Get("filter[status]") {
+	case "1":
+		if !task.IsCompleted {
+			return false
+		}
+	case "0":
+		if task.IsCompleted {
+			return false
+		}
+	}
+
+	return matchResponsible(values, task.ResponsibleUserID) && matchDates(values, task.CreatedAt, task.UpdatedAt)
+}
+
+func matchResponsible(values url.Values, responsibleUserID int) bool {
+	id, _ := strconv.Atoi(values.Get("responsible_user_id"))
+	return id == 0 || id == responsibleUserID
+}
+
+func matchDates(values url.Values, createdAt, updatedAt amocrm.Timestamp) bool {
+	return matchRange(values, "filter[date_create]", createdAt) && matchRange(values, "filter[date_modify]", updatedAt)
+}
+
+func matchRange(values url.Values, key string, ts amocrm.Timestamp) bool {
+	if from, err := strconv.ParseInt(values.Get(key+"[from]"), 10, 64); err == nil && int64(ts) < from {
+		return false
+	}
+	if to, err := strconv.ParseInt(values.Get(key+"[to]"), 10, 64); err == nil && int64(ts) > to {
+		return false
+	}
+
+	return true
+}
+
+func matchModified(r *http.Request, updatedAt amocrm.Timestamp) bool {
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err != nil || int64(updatedAt) >= since.Unix()
+}
+
+// matchQuery emulates amoCRM search by name and custom field values, phone numbers are matched by digits
+func matchQuery(query, name string, fields amocrm.CustomFields) bool {
+	if query == "" {
+		return true
+	}
+
+	query = strings.ToLower(query)
+	queryDigits := digits(query)
+	if strings.Contains(strings.ToLower(name), query) {
+		return true
+	}
+
+	for _, f := range fields {
+		for _, v := range f.Values {
+			if strings.Contains(strings.ToLower(v.Value), query) {
+				return true
+			}
+			if len(queryDigits) > 0 && strings.Contains(digits(v.Value), queryDigits) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func page(items []interface{}, q map[string][]string) []interface{} {
+	values := url.Values(q)
+	limit, err := strconv.Atoi(values.Get("limit_rows"))
+	if err != nil || limit <= 0 || limit > defaultPageSize {
+		limit = defaultPageSize
+	}
+	offset, _ := strconv.Atoi(values.Get("limit_offset"))
+
+	if offset >= len(items) {
+		return nil
+	}
+
+	items = items[offset:]
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+func links(href string) *amocrm.Links {
+	l := new(amocrm.Links)
+	l.Self.Href = href
+	l.Self.Method = http.MethodGet
+
+	return l
+}
+
+// withEmptyObjects reproduces amoCRM serialization of empty arrays as empty objects
+func withEmptyObjects(item interface{}, fields ...string) map[string]interface{} {
+	b, _ := json.Marshal(item)
+
+	m := make(map[string]interface{})
+	_ = json.Unmarshal(b, &m)
+
+	for _, f := range fields {
+		if _, ok := m[f]; !ok {
+			m[f] = struct{}{}
+		}
+	}
+
+	return m
+}
+
+func keyed(items interface{}) map[string]interface{} {
+	b, _ := json.Marshal(items)
+
+	m := make(map[string]interface{})
+	_ = json.Unmarshal(b, &m)
+
+	return m
+}
+
+func orDefault(v, def int) int {
+	if v == 0 {
+		return def
+	}
+
+	return v
+}
+
+func orNow(ts, now amocrm.Timestamp) amocrm.Timestamp {
+	if ts == 0 {
+		return now
+	}
+
+	return ts
+}
+
+// sortedIDs returns sorted keys of a map with int keys
+func sortedIDs(m interface{}) []int {
+	keys := reflect.ValueOf(m).MapKeys()
+
+	ids := make([]int, 0, len(keys))
+	for _, k := range keys {
+		ids = append(ids, int(k.Int()))
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+func containsInt(s []int, v int) bool {
+	for _, n := range s {
+		if n == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,303 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+type (
+	BatchResult struct {
+		Index     int
+		ID        int
+		RequestID int
+		Err       error
+	}
+
+	PostResponseItem struct {
+		ID        int      `json:"id" validate:"omitempty"`
+		RequestID looseInt `json:"request_id,omitempty" validate:"omitempty"`
+		Links     *Links   `json:"_links,omitempty" validate:"omitempty"`
+	}
+
+	PostResponseErrors map[string]map[string]string
+
+	looseInt int
+
+	// retryableRequest marks the payload as safe to retry, which add requests are only with caller provided request ids
+	retryableRequest struct {
+		payload   interface{}
+		retryable bool
+	}
+)
+
+const (
+	// amoCRM API v2 accepts no more than 500 items per request
+	maxBatchSize = 500
+)
+
+func (e *PostResponseErrors) UnmarshalJSON(data []byte) error {
+	actions := make(map[string]json.RawMessage)
+	// empty errors are serialized as an empty array instead of an empty object
+	if err := json.Unmarshal(data, &actions); err != nil {
+		*e = nil
+		return nil
+	}
+
+	result := make(PostResponseErrors, len(actions))
+	for action, raw := range actions {
+		items := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(raw, &items); err != nil {
+			continue
+		}
+
+		messages := make(map[string]string, len(items))
+		for key, msg := range items {
+			var s string
+			if err := json.Unmarshal(msg, &s); err != nil {
+				s = string(msg)
+			}
+			messages[key] = s
+		}
+		result[action] = messages
+	}
+
+	*e = result
+
+	return nil
+}
+
+func (i *looseInt) UnmarshalJSON(data []byte) error {
+	if len(data) > 1 && data[0] == '"' {
+		data = data[1 : len(data)-1]
+	}
+
+	if len(data) == 0 || string(data) == "null" {
+		*i = 0
+		return nil
+	}
+
+	n, err := strconv.Atoi(string(data))
+	if err != nil {
+		return err
+	}
+
+	*i = looseInt(n)
+
+	return nil
+}
+
+func newRetryableRequest(payload interface{}, retryable bool) *retryableRequest {
+	return &retryableRequest{payload: payload, retryable: retryable}
+}
+
+func (r *retryableRequest) idempotent() bool {
+	return r.retryable
+}
+
+func (r *retryableRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.payload)
+}
+
+func newBatchResults(n int) []*BatchResult {
+	results := make([]*BatchResult, n)
+	for i := range results {
+		results[i] = &BatchResult{Index: i}
+	}
+
+	return results
+}
+
+// postAddBatch assigns missing request ids in place and sends new items in chunks,
+// newRequest builds the request of items [from, to) with these request ids.
+// The batch is retried only when all request ids are provided by the caller, since amoCRM deduplicates by them.
+func (c *Client) postAddBatch(ctx context.Context, uri string, requestIDs []int, newRequest func(from, to int) interface{}) ([]*BatchResult, error) {
+	if err := checkUniqueIDs(requestIDs); err != nil {
+		return nil, err
+	}
+
+	autoRequestID := assignRequestIDs(requestIDs)
+
+	results := newBatchResults(len(requestIDs))
+	for i, id := range requestIDs {
+		results[i].RequestID = id
+	}
+
+	err := c.postBatch(ctx, uri, results, false, func(from, to int) interface{} {
+		return newRetryableRequest(newRequest(from, to), !autoRequestID)
+	})
+
+	return results, err
+}
+
+// postUpdateBatch sends updates of the entities with given ids in chunks, newRequest builds the request of items [from, to)
+func (c *Client) postUpdateBatch(ctx context.Context, uri string, ids []int, newRequest func(from, to int) interface{}) ([]*BatchResult, error) {
+	if err := checkUniqueIDs(ids); err != nil {
+		return nil, err
+	}
+
+	results := newBatchResults(len(ids))
+	for i, id := range ids {
+		results[i].ID = id
+	}
+
+	err := c.postBatch(ctx, uri, results, true, newRequest)
+
+	return results, err
+}
+
+// postBatch sends results in chunks, the results must be prefilled with either RequestID (add) or ID (update),
+// which are used to correlate response items back to the inputs.
+func (c *Client) postBatch(ctx context.Context, uri string, results []*BatchResult, update bool, newRequest func(from, to int) interface{}) error {
+	for from := 0; from < len(results); from += maxBatchSize {
+		to := from + maxBatchSize
+		if to > len(results) {
+			to = len(results)
+		}
+
+		chunk := results[from:to]
+		body, err := c.doPost(ctx, c.baseURL+uri, newRequest(from, to))
+		if err == nil {
+			err = correlateBatch(body, chunk, update)
+		}
+
+		if err != nil {
+			for _, r := range chunk {
+				r.Err = err
+			}
+		}
+
+		if ctx.Err() != nil {
+			for _, r := range results[to:] {
+				r.Err = ctx.Err()
+			}
+			break
+		}
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			return ErrBatchPartialFailure
+		}
+	}
+
+	return nil
+}
+
+func correlateBatch(body []byte, chunk []*BatchResult, update bool) error {
+	result := new(PostResponse)
+	err := json.Unmarshal(body, result)
+	if err != nil {
+		amoError := new(AmoError)
+		err = json.Unmarshal(body, amoError)
+		if err != nil {
+			return err
+		}
+
+		return amoError
+	}
+
+	if result.Response != nil {
+		return result.Response
+	}
+
+	// fall back to positional correlation, when request_id is not echoed back
+	if !update && len(result.Embedded.Errors) == 0 && len(result.Embedded.Items) == len(chunk) && !hasRequestIDs(result.Embedded.Items) {
+		for i, item := range result.Embedded.Items {
+			chunk[i].ID = item.ID
+		}
+
+		return nil
+	}
+
+	pending := make(map[int]*BatchResult, len(chunk))
+	for _, r := range chunk {
+		if update {
+			pending[r.ID] = r
+		} else {
+			pending[r.RequestID] = r
+		}
+	}
+
+	for _, item := range result.Embedded.Items {
+		key := int(item.RequestID)
+		if update {
+			key = item.ID
+		}
+
+		if r, ok := pending[key]; ok {
+			r.ID = item.ID
+			delete(pending, key)
+		}
+	}
+
+	for _, messages := range result.Embedded.Errors {
+		for key, msg := range messages {
+			id, err := strconv.Atoi(key)
+			if err != nil {
+				continue
+			}
+
+			if r, ok := pending[id]; ok {
+				r.Err = errors.New(msg)
+				delete(pending, id)
+			}
+		}
+	}
+
+	for _, r := range pending {
+		r.Err = ErrBatchItemNotProcessed
+	}
+
+	return nil
+}
+
+func hasRequestIDs(items []*PostResponseItem) bool {
+	for _, item := range items {
+		if item.RequestID != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkUniqueIDs rejects repeated non-zero ids, since response items are correlated with the inputs by them
+func checkUniqueIDs(ids []int) error {
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if id == 0 {
+			continue
+		}
+
+		if seen[id] {
+			return fmt.Errorf("%d: %w", id, ErrDuplicateBatchItem)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// assignRequestIDs fills zero request ids with unique values, so that every item could be correlated with the response
+func assignRequestIDs(ids []int) bool {
+	max := 0
+	for _, id := range ids {
+		if id > max {
+			max = id
+		}
+	}
+
+	assigned := false
+	for i := range ids {
+		if ids[i] == 0 {
+			max++
+			ids[i] = max
+			assigned = true
+		}
+	}
+
+	return assigned
+}
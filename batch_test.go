@@ -0,0 +1,112 @@
+package amocrm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+func TestAddBatchCorrelation(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		requestIDs []int
+		failures   int
+		requests   int
+	}{
+		{name: "auto request ids", requestIDs: []int{0, 0, 0}, requests: 1},
+		{name: "mixed request ids", requestIDs: []int{0, 7, 0}, requests: 1},
+		{name: "caller request ids are retried", requestIDs: []int{3, 1, 2}, failures: 1, requests: 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestClient(t)
+
+			leads := make([]*amocrm.LeadAdd, len(tt.requestIDs))
+			for i, id := range tt.requestIDs {
+				leads[i] = &amocrm.LeadAdd{Name: "lead " + string(rune('a'+i)), StatusID: 10, PipelineID: amocrmtest.DefaultPipelineID, RequestID: id}
+			}
+
+			if tt.failures > 0 {
+				s.Fail(&amocrmtest.Failure{Method: http.MethodPost, Path: "/api/v2/leads", StatusCode: http.StatusBadGateway, Times: tt.failures})
+			}
+
+			results, err := c.AddLeads(context.Background(), leads)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, lead := range leads {
+				if stored := s.Lead(results[i].ID); stored == nil || stored.Name != lead.Name {
+					t.Fatalf("result %d is not correlated with %q: %+v", i, lead.Name, stored)
+				}
+
+				if lead.RequestID != tt.requestIDs[i] {
+					t.Fatalf("input %d is modified", i)
+				}
+			}
+
+			if n := countRequests(s, http.MethodPost, "/api/v2/leads"); n != tt.requests {
+				t.Fatalf("expected %d requests, got %d", tt.requests, n)
+			}
+		})
+	}
+}
+
+func TestAddBatchNotRetriedWithAutoRequestIDs(t *testing.T) {
+	s, c := newTestClient(t)
+
+	s.Fail(&amocrmtest.Failure{Method: http.MethodPost, Path: "/api/v2/leads", StatusCode: http.StatusBadGateway})
+	results, err := c.AddLeads(context.Background(), []*amocrm.LeadAdd{{Name: "lead", StatusID: 10, PipelineID: amocrmtest.DefaultPipelineID}})
+	if !errors.Is(err, amocrm.ErrBatchPartialFailure) || results[0].Err == nil {
+		t.Fatalf("expected the failed item, got %v", err)
+	}
+
+	if n := countRequests(s, http.MethodPost, "/api/v2/leads"); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+}
+
+func TestUpdateBatchPartialFailure(t *testing.T) {
+	s, c := newTestClient(t)
+	staleID, freshID := addTestLead(t, c, "stale"), addTestLead(t, c, "fresh")
+
+	results, err := c.UpdateLeads(context.Background(), []*amocrm.LeadUpdate{
+		{ID: staleID, UpdatedAt: 1, Name: "stale!"},
+		{ID: freshID, UpdatedAt: amocrm.NewTimestamp(time.Now().Add(time.Minute)), Name: "fresh!"},
+	})
+	if !errors.Is(err, amocrm.ErrBatchPartialFailure) {
+		t.Fatalf("expected ErrBatchPartialFailure, got %v", err)
+	}
+
+	if len(results) != 2 || !amocrm.IsUpdateConflict(results[0].Err) || results[1].Err != nil || results[1].ID != freshID {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	if s.Lead(staleID).Name != "stale" || s.Lead(freshID).Name != "fresh!" {
+		t.Fatal("only the fresh lead must be updated")
+	}
+}
+
+func TestBatchDuplicateItems(t *testing.T) {
+	s, c := newTestClient(t)
+	ctx := context.Background()
+	id := addTestLead(t, c, "lead")
+
+	_, err := c.AddLeads(ctx, []*amocrm.LeadAdd{{Name: "a", StatusID: 10, RequestID: 1}, {Name: "b", StatusID: 10, RequestID: 1}})
+	if !errors.Is(err, amocrm.ErrDuplicateBatchItem) {
+		t.Fatalf("expected ErrDuplicateBatchItem for request ids, got %v", err)
+	}
+
+	_, err = c.UpdateLeads(ctx, []*amocrm.LeadUpdate{{ID: id, UpdatedAt: 1}, {ID: id, UpdatedAt: 2}})
+	if !errors.Is(err, amocrm.ErrDuplicateBatchItem) {
+		t.Fatalf("expected ErrDuplicateBatchItem for ids, got %v", err)
+	}
+
+	if n := countRequests(s, http.MethodPost, "/api/v2/leads"); n != 1 {
+		t.Fatalf("duplicates must be rejected before sending, got %d requests", n)
+	}
+}
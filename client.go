@@ -8,7 +8,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"strconv"
 	"sync"
 	"time"
 
@@ -19,27 +18,35 @@ type (
 	ClientOption func(c *Client)
 
 	Client struct {
-		userLogin string
-		apiHash   string
-		timezone  string
-		baseURL   string
-		cookie    []*http.Cookie
-		client    *http.Client
-		validator *validator.Validate
-		mu        sync.RWMutex
+		userLogin      string
+		apiHash        string
+		timezone       string
+		businessDayEnd time.Duration
+		baseURL        string
+		cookie         []*http.Cookie
+		authGen        uint64
+		oauth          *oauth2Config
+		limiter        *rateLimiter
+		retry          *RetryPolicy
+		client         *http.Client
+		validator      *validator.Validate
+		mu             sync.RWMutex
 	}
 
 	PostResponse struct {
 		ID        int `json:"id" validate:"omitempty"`
 		RequestID int `json:"request_id" validate:"omitempty"`
 		Embedded  struct {
-			Items []struct {
-				ID int `json:"id" validate:"omitempty"`
-			} `json:"items" validate:"required,dive,required"`
+			Items  []*PostResponseItem `json:"items" validate:"required,dive,required"`
+			Errors PostResponseErrors  `json:"errors,omitempty" validate:"omitempty"`
 		} `json:"_embedded" validate:"omitempty"`
 		Response *AmoError `json:"response" validate:"omitempty"`
 	}
 
+	ErrorResponse struct {
+		Response *AmoError `json:"response" validate:"omitempty"`
+	}
+
 	AuthResponse struct {
 		Response struct {
 			Auth       bool           `json:"auth" validate:"required"`
@@ -49,29 +56,33 @@ type (
 			Error      string         `json:"error" validate:"omitempty"`
 		} `json:"response" validate:"required"`
 	}
+
+	authState struct {
+		token *Token
+		gen   uint64
+	}
 )
 
 const (
-	authURI      = "/private/api/auth.php?type=json"
-	notesURI     = "/api/v2/notes"
-	contactsURI  = "/api/v2/contacts"
-	accountURI   = "/api/v2/account"
-	leadsURI     = "/api/v2/leads"
-	tasksURI     = "/api/v2/tasks"
-	pipelinesURI = "/api/v2/pipelines"
-	downloadURI  = "/download/"
+	authURI                = "/private/api/auth.php?type=json"
+	notesURI               = "/api/v2/notes"
+	contactsURI            = "/api/v2/contacts"
+	companiesURI           = "/api/v2/companies"
+	customersURI           = "/api/v2/customers"
+	accountURI             = "/api/v2/account"
+	leadsURI               = "/api/v2/leads"
+	tasksURI               = "/api/v2/tasks"
+	pipelinesURI           = "/api/v2/pipelines"
+	transactionsURI        = "/api/v2/transactions"
+	webhooksURI            = "/api/v2/webhooks"
+	webhooksSubscribeURI   = "/api/v2/webhooks/subscribe"
+	webhooksUnsubscribeURI = "/api/v2/webhooks/unsubscribe"
+	downloadURI            = "/download/"
 
 	defaultHTTPTimeout = 5 * time.Second
 )
 
 func NewClient(accountURL string, login string, hash string, opts ...ClientOption) (*Client, error) {
-	if login == "" {
-		return nil, ErrEmptyLogin
-	}
-	if hash == "" {
-		return nil, ErrEmptyAPIHash
-	}
-
 	_, err := url.Parse(accountURL)
 	if err != nil {
 		return nil, err
@@ -85,13 +96,31 @@ func NewClient(accountURL string, login string, hash string, opts ...ClientOptio
 			Transport: http.DefaultTransport,
 			Timeout:   defaultHTTPTimeout,
 		},
-		validator: validator.New(),
+		validator:      validator.New(),
+		limiter:        newRateLimiter(defaultRateLimit, defaultRateBurst),
+		retry:          DefaultRetryPolicy(),
+		businessDayEnd: defaultBusinessDayEnd,
 	}
 
 	for _, o := range opts {
 		o(c)
 	}
 
+	if c.oauth != nil {
+		if err := c.oauth.validate(); err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}
+
+	if login == "" {
+		return nil, ErrEmptyLogin
+	}
+	if hash == "" {
+		return nil, ErrEmptyAPIHash
+	}
+
 	return c, nil
 }
 
@@ -102,6 +131,19 @@ func WithHTTPTimeout(d time.Duration) ClientOption {
 }
 
 func (c *Client) Authorize(ctx context.Context) error {
+	if c.oauth != nil {
+		_, err := c.oauthToken(ctx)
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.authorize(ctx)
+}
+
+// authorize must be called with c.mu locked
+func (c *Client) authorize(ctx context.Context) error {
 	values := url.Values{}
 	values.Set("USER_LOGIN", c.userLogin)
 	values.Set("USER_HASH", c.apiHash)
@@ -119,19 +161,18 @@ func (c *Client) Authorize(ctx context.Context) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return errors.New("http status not ok: " + strconv.Itoa(resp.StatusCode))
-	}
-
-	c.mu.Lock()
-	c.cookie = resp.Cookies()
-	c.mu.Unlock()
-
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 
+	if resp.StatusCode != 200 {
+		return newHTTPError(resp, body)
+	}
+
+	c.cookie = resp.Cookies()
+	c.authGen++
+
 	authResponse := new(AuthResponse)
 	err = json.Unmarshal(body, authResponse)
 	if err != nil {
@@ -158,76 +199,168 @@ func (c *Client) DownloadAttachment(ctx context.Context, attachment string) ([]b
 }
 
 func (c *Client) doGet(ctx context.Context, url string, params map[string]string) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	return c.doGetWithHeader(ctx, url, params, nil)
+}
+
+func (c *Client) doGetWithHeader(ctx context.Context, url string, params map[string]string, header http.Header) ([]byte, error) {
+	return c.do(ctx, c.retry.allowsMethod(http.MethodGet), func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		q := req.URL.Query()
+		for k, v := range params {
+			q.Add(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+
+		return req, nil
+	})
+}
+
+func (c *Client) doPost(ctx context.Context, url string, data interface{}) ([]byte, error) {
+	return c.doJSON(ctx, http.MethodPost, url, data)
+}
+
+func (c *Client) doPatch(ctx context.Context, url string, data interface{}) ([]byte, error) {
+	return c.doJSON(ctx, http.MethodPatch, url, data)
+}
+
+func (c *Client) doJSON(ctx context.Context, method string, url string, data interface{}) ([]byte, error) {
+	reqBody, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
 
-	c.mu.RLock()
-	for _, cookie := range c.cookie {
-		req.AddCookie(cookie)
+	// POST requests could be safely retried only when duplicates can be detected by request_id
+	idempotent := method != http.MethodPost
+	if r, ok := data.(idempotentRequest); ok {
+		idempotent = r.idempotent()
 	}
-	c.mu.RUnlock()
+	idempotent = idempotent && c.retry.allowsMethod(method)
+
+	return c.do(ctx, idempotent, func() (*http.Request, error) {
+		req, err := http.NewRequest(method, url, bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+
+		return req, nil
+	})
+}
+
+func (c *Client) do(ctx context.Context, idempotent bool, newRequest func() (*http.Request, error)) ([]byte, error) {
+	reauthorized := false
+	for attempt := 1; ; attempt++ {
+		resp, body, state, err := c.send(ctx, newRequest)
+		if err == nil && !reauthorized && isUnauthorized(resp.StatusCode, body) {
+			// session or access token could expire at any moment, so authorize again and replay the request once
+			reauthorized = true
+			if err := c.reauthorize(ctx, state); err != nil {
+				return nil, err
+			}
+
+			resp, body, _, err = c.send(ctx, newRequest)
+		}
 
-	q := req.URL.Query()
-	for k, v := range params {
-		q.Add(k, v)
+		if !idempotent || attempt >= c.retry.attempts() || !c.retry.retryable(ctx, err, resp, body) {
+			if err != nil {
+				return nil, err
+			}
+
+			if resp.StatusCode >= 400 {
+				return nil, newHTTPError(resp, body)
+			}
+
+			return body, nil
+		}
+
+		if err := sleep(ctx, c.retry.delay(attempt, resp)); err != nil {
+			return nil, err
+		}
 	}
-	req.URL.RawQuery = q.Encode()
+}
 
-	resp, err := c.client.Do(req.WithContext(ctx))
+func (c *Client) send(ctx context.Context, newRequest func() (*http.Request, error)) (*http.Response, []byte, *authState, error) {
+	req, err := newRequest()
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return nil, errors.New("http status not ok: " + strconv.Itoa(resp.StatusCode))
-	}
+	state := new(authState)
+	if c.oauth != nil {
+		state.token, err = c.oauthToken(ctx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+		req.Header.Set("Authorization", "Bearer "+state.token.AccessToken)
+	} else {
+		c.mu.RLock()
+		for _, cookie := range c.cookie {
+			req.AddCookie(cookie)
+		}
+		state.gen = c.authGen
+		c.mu.RUnlock()
 	}
 
-	return body, nil
-}
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, nil, nil, err
+	}
 
-func (c *Client) doPost(ctx context.Context, url string, data interface{}) ([]byte, error) {
-	reqBody, err := json.Marshal(data)
+	resp, err := c.client.Do(req.WithContext(ctx))
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(reqBody))
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return resp, body, state, nil
+}
 
-	c.mu.RLock()
-	for _, cookie := range c.cookie {
-		req.AddCookie(cookie)
+func (c *Client) reauthorize(ctx context.Context, state *authState) error {
+	if c.oauth != nil {
+		_, err := c.refreshOAuthToken(ctx, state.token)
+		return err
 	}
-	c.mu.RUnlock()
 
-	resp, err := c.client.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, err
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// session has already been renewed by a concurrent caller
+	if c.authGen != state.gen {
+		return nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return nil, errors.New("http status not ok: " + strconv.Itoa(resp.StatusCode))
+	return c.authorize(ctx)
+}
+
+func isUnauthorized(statusCode int, body []byte) bool {
+	return statusCode == http.StatusUnauthorized || responseErrorCode(body) == InvalidCredentialsCode
+}
+
+func responseErrorCode(body []byte) int {
+	if !bytes.Contains(body, []byte(`"error_code"`)) {
+		return 0
 	}
 
-	respBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	errorResponse := new(ErrorResponse)
+	if err := json.Unmarshal(body, errorResponse); err != nil || errorResponse.Response == nil {
+		return 0
 	}
 
-	return respBody, nil
+	return errorResponse.Response.ErrorCode
 }
 
 func (c *Client) getResponseID(body []byte) (int, error) {
@@ -0,0 +1,67 @@
+package amocrm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+const (
+	authPath      = "/private/api/auth.php"
+	leadsPath     = "/api/v2/leads"
+	pipelinesPath = "/api/v2/pipelines"
+)
+
+func TestReauthorize(t *testing.T) {
+	tests := []struct {
+		name   string
+		expire func(s *amocrmtest.Server)
+		err    error
+	}{
+		{
+			name:   "expired session",
+			expire: func(s *amocrmtest.Server) { s.ExpireSessions() },
+		},
+		{
+			name: "invalid credentials with 200 OK",
+			expire: func(s *amocrmtest.Server) {
+				s.Fail(&amocrmtest.Failure{Method: http.MethodGet, Path: pipelinesPath, StatusCode: http.StatusOK, Body: amocrmtest.ResponseError(amocrm.InvalidCredentialsCode, "Authorization failed")})
+			},
+		},
+		{
+			name: "unauthorized after reauthorization",
+			expire: func(s *amocrmtest.Server) {
+				s.Fail(&amocrmtest.Failure{Method: http.MethodGet, Path: pipelinesPath, StatusCode: http.StatusUnauthorized, Times: 2})
+			},
+			err: amocrm.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestClient(t)
+			tt.expire(s)
+
+			_, err := c.GetPipelines(context.Background(), &amocrm.PipelineRequestParams{})
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if n := countRequests(s, http.MethodPost, authPath); n != 2 {
+				t.Fatalf("expected 2 authorizations, got %d", n)
+			}
+
+			if n := countRequests(s, http.MethodGet, pipelinesPath); n != 2 {
+				t.Fatalf("expected the request to be replayed once, got %d requests", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+type (
+	CompanyRequestParams struct {
+		ID                []int  `validate:"omitempty,gt=0,dive,required"`
+		LimitRows         int    `validate:"required_with=LimitOffset,lte=500"`
+		LimitOffset       int    `validate:"omitempty"`
+		ResponsibleUserID int    `validate:"omitempty"`
+		Query             string `validate:"omitempty"`
+	}
+
+	CompanyAdd struct {
+		Name              string               `json:"name" validate:"required"`
+		CreatedAt         Timestamp            `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp            `json:"updated_at,omitempty" validate:"omitempty"`
+		ResponsibleUserID int                  `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
+		CreatedBy         int                  `json:"created_by,string,omitempty" validate:"omitempty"`
+		Tags              string               `json:"tags,omitempty" validate:"omitempty"`
+		LeadsID           []string             `json:"leads_id,omitempty" validate:"omitempty,gt=0,dive,required"`
+		CustomersID       []string             `json:"customers_id,omitempty" validate:"omitempty,gt=0,dive,required"`
+		ContactsID        []string             `json:"contacts_id,omitempty" validate:"omitempty,gt=0,dive,required"`
+		CustomFields      []*UpdateCustomField `json:"custom_fields,omitempty" validate:"omitempty,gt=0,dive,required"`
+		RequestID         int                  `json:"request_id,string,omitempty" validate:"omitempty"`
+	}
+
+	CompanyUpdate struct {
+		ID                int                  `json:"id,string" validate:"required"`
+		Name              string               `json:"name,omitempty" validate:"omitempty"`
+		CreatedAt         Timestamp            `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp            `json:"updated_at" validate:"required"`
+		ResponsibleUserID int                  `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
+		CreatedBy         int                  `json:"created_by,string,omitempty" validate:"omitempty"`
+		Tags              string               `json:"tags,omitempty" validate:"omitempty"`
+		LeadsID           []string             `json:"leads_id,omitempty" validate:"omitempty,gt=0,dive,required"`
+		CustomersID       []string             `json:"customers_id,omitempty" validate:"omitempty,gt=0,dive,required"`
+		ContactsID        []string             `json:"contacts_id,omitempty" validate:"omitempty,gt=0,dive,required"`
+		CustomFields      []*UpdateCustomField `json:"custom_fields,omitempty" validate:"omitempty,gt=0,dive,required"`
+		Unlink            *Unlink              `json:"unlink,omitempty" validate:"omitempty"`
+	}
+
+	AddCompanyRequest struct {
+		Add []*CompanyAdd `json:"add" validate:"required,dive,required"`
+	}
+
+	UpdateCompanyRequest struct {
+		Update []*CompanyUpdate `json:"update" validate:"required,dive,required"`
+	}
+
+	GetCompanyResponse struct {
+		Links    *Links `json:"_links" validate:"omitempty"`
+		Embedded struct {
+			Items []*Company `json:"items" validate:"required,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+		Response *AmoError `json:"response,omitempty" validate:"omitempty"`
+	}
+
+	Company struct {
+		ID                int       `json:"id" validate:"required"`
+		Name              string    `json:"name" validate:"required"`
+		ResponsibleUserID int       `json:"responsible_user_id" validate:"required"`
+		CreatedBy         int       `json:"created_by" validate:"required"`
+		CreatedAt         Timestamp `json:"created_at" validate:"required"`
+		UpdatedAt         Timestamp `json:"updated_at" validate:"required"`
+		AccountID         int       `json:"account_id" validate:"required"`
+		UpdatedBy         int       `json:"updated_by" validate:"required"`
+		GroupID           int       `json:"group_id,omitempty" validate:"omitempty"`
+		Contacts          struct {
+			ID    []int  `json:"id" validate:"omitempty,dive,required"`
+			Links *Links `json:"_links" validate:"omitempty"`
+		} `json:"contacts,omitempty" validate:"omitempty"`
+		Leads struct {
+			ID    []int  `json:"id" validate:"omitempty,dive,required"`
+			Links *Links `json:"_links" validate:"omitempty"`
+		} `json:"leads,omitempty" validate:"omitempty"`
+		Customers struct {
+			ID    []int  `json:"id" validate:"omitempty,dive,required"`
+			Links *Links `json:"_links" validate:"omitempty"`
+		} `json:"customers,omitempty" validate:"omitempty"`
+		ClosestTaskAt Timestamp    `json:"closest_task_at,omitempty" validate:"omitempty"`
+		Tags          []*Tag       `json:"tags,omitempty" validate:"omitempty,dive,required"`
+		CustomFields  CustomFields `json:"custom_fields,omitempty" validate:"omitempty,dive,required"`
+		Links         *Links       `json:"_links" validate:"required"`
+	}
+)
+
+var (
+	companyArrayFields = [][]byte{
+		[]byte("tags"),
+		[]byte("custom_fields"),
+	}
+)
+
+func (c *Client) AddCompany(ctx context.Context, company *CompanyAdd) (int, error) {
+	if err := c.validator.Struct(company); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.doPost(ctx, c.baseURL+companiesURI, newRetryableRequest(&AddCompanyRequest{Add: []*CompanyAdd{company}}, company.RequestID != 0))
+	if err != nil {
+		return 0, err
+	}
+
+	return c.getResponseID(resp)
+}
+
+func (c *Client) UpdateCompany(ctx context.Context, company *CompanyUpdate) (int, error) {
+	if err := c.validator.Struct(company); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.doPost(ctx, c.baseURL+companiesURI, &UpdateCompanyRequest{Update: []*CompanyUpdate{company}})
+	if err != nil {
+		return 0, err
+	}
+
+	return c.getResponseID(resp)
+}
+
+func (c *Client) AddCompanies(ctx context.Context, companies []*CompanyAdd) ([]*BatchResult, error) {
+	if err := c.validator.Var(companies, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	requestIDs := make([]int, len(companies))
+	for i, company := range companies {
+		requestIDs[i] = company.RequestID
+	}
+
+	return c.postAddBatch(ctx, companiesURI, requestIDs, func(from, to int) interface{} {
+		batch := make([]*CompanyAdd, 0, to-from)
+		for i, company := range companies[from:to] {
+			item := *company
+			item.RequestID = requestIDs[from+i]
+			batch = append(batch, &item)
+		}
+
+		return &AddCompanyRequest{Add: batch}
+	})
+}
+
+func (c *Client) UpdateCompanies(ctx context.Context, companies []*CompanyUpdate) ([]*BatchResult, error) {
+	if err := c.validator.Var(companies, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(companies))
+	for i, company := range companies {
+		ids[i] = company.ID
+	}
+
+	return c.postUpdateBatch(ctx, companiesURI, ids, func(from, to int) interface{} {
+		return &UpdateCompanyRequest{Update: companies[from:to]}
+	})
+}
+
+func (c *Client) GetCompanies(ctx context.Context, reqParams *CompanyRequestParams) ([]*Company, error) {
+	if err := c.validator.Struct(reqParams); err != nil {
+		return nil, err
+	}
+
+	addValues := make(map[string]string)
+	if reqParams.ID != nil {
+		addValues["id"] = joinIntSlice(reqParams.ID)
+	}
+	if reqParams.LimitRows != 0 {
+		addValues["limit_rows"] = strconv.Itoa(reqParams.LimitRows)
+		if reqParams.LimitOffset != 0 {
+			addValues["limit_offset"] = strconv.Itoa(reqParams.LimitOffset)
+		}
+	}
+	if reqParams.ResponsibleUserID != 0 {
+		addValues["responsible_user_id"] = strconv.Itoa(reqParams.ResponsibleUserID)
+	}
+	if reqParams.Query != "" {
+		addValues["query"] = reqParams.Query
+	}
+
+	body, err := c.doGet(ctx, c.baseURL+companiesURI, addValues)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	companyResponse := new(GetCompanyResponse)
+	err = json.Unmarshal(body, companyResponse)
+	if err != nil {
+		// fix bad json serialization, where nil array is serialized as nil object
+		body = fixBadArraySerialization(body, companyArrayFields)
+		err = json.Unmarshal(body, companyResponse)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if companyResponse.Response != nil {
+		return nil, companyResponse.Response
+	}
+
+	err = c.validator.Struct(companyResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(companyResponse.Embedded.Items) == 0 {
+		return nil, ErrEmptyResponseItems
+	}
+
+	return companyResponse.Embedded.Items, nil
+}
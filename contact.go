@@ -4,23 +4,32 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type (
 	ContactRequestParams struct {
-		ID                []int  `validate:"omitempty,gt=0,dive,required"`
-		LimitRows         int    `validate:"required_with=LimitOffset,lte=500"`
-		LimitOffset       int    `validate:"omitempty"`
-		ResponsibleUserID int    `validate:"omitempty"`
-		Query             string `validate:"omitempty"`
+		ID                []int                 `validate:"omitempty,gt=0,dive,required"`
+		LimitRows         int                   `validate:"required_with=LimitOffset,lte=500"`
+		LimitOffset       int                   `validate:"omitempty"`
+		ResponsibleUserID int                   `validate:"omitempty"`
+		Query             string                `validate:"omitempty"`
+		Filter            *ContactRequestFilter `validate:"omitempty"`
+		IfModifiedSince   time.Time             `validate:"omitempty"`
+	}
+
+	ContactRequestFilter struct {
+		CreatedAt *DateRangeFilter `validate:"omitempty"`
+		UpdatedAt *DateRangeFilter `validate:"omitempty"`
 	}
 
 	ContactAdd struct {
 		Name              string               `json:"name" validate:"required"`
-		CreatedAt         int                  `json:"created_at,string,omitempty" validate:"omitempty"`
-		UpdatedAt         int                  `json:"updated_at,string,omitempty" validate:"omitempty"`
+		CreatedAt         Timestamp            `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp            `json:"updated_at,omitempty" validate:"omitempty"`
 		ResponsibleUserID int                  `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
 		CreatedBy         int                  `json:"created_by,string,omitempty" validate:"omitempty"`
 		CompanyName       string               `json:"company_name,omitempty" validate:"omitempty"`
@@ -29,13 +38,14 @@ type (
 		CustomersID       int                  `json:"customers_id,string,omitempty" validate:"omitempty"`
 		CompanyID         int                  `json:"company_id,string,omitempty" validate:"omitempty"`
 		CustomFields      []*UpdateCustomField `json:"custom_fields,omitempty" validate:"omitempty,gt=0,required"`
+		RequestID         int                  `json:"request_id,string,omitempty" validate:"omitempty"`
 	}
 
 	ContactUpdate struct {
 		ID                int                  `json:"id,string" validate:"required"`
 		Name              string               `json:"name,omitempty" validate:"omitempty"`
-		CreatedAt         int                  `json:"created_at,string,omitempty" validate:"omitempty"`
-		UpdatedAt         int                  `json:"updated_at,string" validate:"required"`
+		CreatedAt         Timestamp            `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp            `json:"updated_at" validate:"required"`
 		ResponsibleUserID int                  `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
 		CreatedBy         int                  `json:"created_by,string,omitempty" validate:"omitempty"`
 		CompanyName       string               `json:"company_name,omitempty" validate:"omitempty"`
@@ -64,15 +74,15 @@ type (
 	}
 
 	Contact struct {
-		ID                int    `json:"id" validate:"required"`
-		Name              string `json:"name" validate:"required"`
-		ResponsibleUserID int    `json:"responsible_user_id" validate:"required"`
-		CreatedBy         int    `json:"created_by" validate:"required"`
-		CreatedAt         int    `json:"created_at" validate:"required"`
-		UpdatedAt         int    `json:"updated_at" validate:"required"`
-		AccountID         int    `json:"account_id" validate:"required"`
-		UpdatedBy         int    `json:"updated_by" validate:"required"`
-		GroupID           int    `json:"group_id,omitempty" validate:"omitempty"`
+		ID                int       `json:"id" validate:"required"`
+		Name              string    `json:"name" validate:"required"`
+		ResponsibleUserID int       `json:"responsible_user_id" validate:"required"`
+		CreatedBy         int       `json:"created_by" validate:"required"`
+		CreatedAt         Timestamp `json:"created_at" validate:"required"`
+		UpdatedAt         Timestamp `json:"updated_at" validate:"required"`
+		AccountID         int       `json:"account_id" validate:"required"`
+		UpdatedBy         int       `json:"updated_by" validate:"required"`
+		GroupID           int       `json:"group_id,omitempty" validate:"omitempty"`
 		Company           struct {
 			ID    int    `json:"id" validate:"omitempty"`
 			Name  string `json:"name" validate:"omitempty"`
@@ -82,9 +92,9 @@ type (
 			ID    []int  `json:"id" validate:"omitempty,dive,required"`
 			Links *Links `json:"_links" validate:"omitempty"`
 		} `json:"leads,omitempty" validate:"omitempty"`
-		ClosestTaskAt int            `json:"closest_task_at,omitempty" validate:"omitempty"`
-		Tags          []*Tag         `json:"tags,omitempty" validate:"omitempty,dive,required"`
-		CustomFields  []*CustomField `json:"custom_fields,omitempty" validate:"omitempty,dive,required"`
+		ClosestTaskAt Timestamp    `json:"closest_task_at,omitempty" validate:"omitempty"`
+		Tags          []*Tag       `json:"tags,omitempty" validate:"omitempty,dive,required"`
+		CustomFields  CustomFields `json:"custom_fields,omitempty" validate:"omitempty,dive,required"`
 		Customers     struct {
 		} `json:"customers,omitempty" validate:"omitempty"`
 		Links *Links `json:"_links" validate:"required"`
@@ -103,7 +113,7 @@ func (c *Client) AddContact(ctx context.Context, contact *ContactAdd) (int, erro
 		return 0, err
 	}
 
-	resp, err := c.doPost(ctx, c.baseURL+contactsURI, &AddContactRequest{Add: []*ContactAdd{contact}})
+	resp, err := c.doPost(ctx, c.baseURL+contactsURI, newRetryableRequest(&AddContactRequest{Add: []*ContactAdd{contact}}, contact.RequestID != 0))
 	if err != nil {
 		return 0, err
 	}
@@ -124,6 +134,43 @@ func (c *Client) UpdateContact(ctx context.Context, contact *ContactUpdate) (int
 	return c.getResponseID(resp)
 }
 
+func (c *Client) AddContacts(ctx context.Context, contacts []*ContactAdd) ([]*BatchResult, error) {
+	if err := c.validator.Var(contacts, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	requestIDs := make([]int, len(contacts))
+	for i, contact := range contacts {
+		requestIDs[i] = contact.RequestID
+	}
+
+	return c.postAddBatch(ctx, contactsURI, requestIDs, func(from, to int) interface{} {
+		batch := make([]*ContactAdd, 0, to-from)
+		for i, contact := range contacts[from:to] {
+			item := *contact
+			item.RequestID = requestIDs[from+i]
+			batch = append(batch, &item)
+		}
+
+		return &AddContactRequest{Add: batch}
+	})
+}
+
+func (c *Client) UpdateContacts(ctx context.Context, contacts []*ContactUpdate) ([]*BatchResult, error) {
+	if err := c.validator.Var(contacts, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(contacts))
+	for i, contact := range contacts {
+		ids[i] = contact.ID
+	}
+
+	return c.postUpdateBatch(ctx, contactsURI, ids, func(from, to int) interface{} {
+		return &UpdateContactRequest{Update: contacts[from:to]}
+	})
+}
+
 func (c *Client) GetContacts(ctx context.Context, reqParams *ContactRequestParams) ([]*Contact, error) {
 	if err := c.validator.Struct(reqParams); err != nil {
 		return nil, err
@@ -149,7 +196,12 @@ func (c *Client) GetContacts(ctx context.Context, reqParams *ContactRequestParam
 		addValues["query"] = reqParams.Query
 	}
 
-	body, err := c.doGet(ctx, c.baseURL+contactsURI, addValues)
+	if reqParams.Filter != nil {
+		addDateRangeFilter(addValues, "filter[date_create]", reqParams.Filter.CreatedAt)
+		addDateRangeFilter(addValues, "filter[date_modify]", reqParams.Filter.UpdatedAt)
+	}
+
+	body, err := c.doGetWithHeader(ctx, c.baseURL+contactsURI, addValues, ifModifiedSinceHeader(reqParams.IfModifiedSince))
 	if err != nil {
 		return nil, err
 	}
@@ -209,3 +261,25 @@ func fixBadArraySerialization(body []byte, fields [][]byte) []byte {
 
 	return body
 }
+
+func (c *Client) getContact(ctx context.Context, contactID int) (*Contact, error) {
+	if contactID == 0 {
+		return nil, ErrEmptyEntityID
+	}
+
+	contacts, err := c.GetContacts(ctx, &ContactRequestParams{ID: []int{contactID}})
+	if errors.Is(err, ErrEmptyResponseItems) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contact := range contacts {
+		if contact.ID == contactID {
+			return contact, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
@@ -0,0 +1,170 @@
+package amocrm
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	// national significant number length in Russia and Kazakhstan, where amoCRM is used the most
+	nationalPhoneLength = 10
+)
+
+// NormalizePhone converts a phone number to the E.164 format, national numbers with 8 trunk prefix
+// or without country code are considered to be Russian (+7).
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	digits := b.String()
+	switch {
+	case digits == "":
+		return ""
+	case len(digits) == nationalPhoneLength:
+		digits = "7" + digits
+	case len(digits) == nationalPhoneLength+1 && digits[0] == '8' && !strings.HasPrefix(strings.TrimSpace(phone), "+"):
+		digits = "7" + digits[1:]
+	}
+
+	return "+" + digits
+}
+
+// NormalizeEmail trims spaces around an email and lowercases it, so emails differing only in case are matched.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// FindContactByPhone returns contacts, which have the phone among PHONE field values in any format
+func (c *Client) FindContactByPhone(ctx context.Context, phone string) ([]*Contact, error) {
+	phone = NormalizePhone(phone)
+	if phone == "" {
+		return nil, ErrEmptyPhoneNumber
+	}
+
+	// amoCRM searches phones by substring, so the query without country code matches any stored format
+	query := phone[1:]
+	if len(query) > nationalPhoneLength {
+		query = query[len(query)-nationalPhoneLength:]
+	}
+
+	return c.findContacts(ctx, query, PhoneFieldCode, func(value string) bool {
+		return NormalizePhone(value) == phone
+	})
+}
+
+func (c *Client) FindContactByEmail(ctx context.Context, email string) ([]*Contact, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return nil, ErrEmptyEmail
+	}
+
+	return c.findContacts(ctx, email, EmailFieldCode, func(value string) bool {
+		return NormalizeEmail(value) == email
+	})
+}
+
+// FindOrCreateContact returns the first contact found by phone or email,
+// otherwise it creates the contact with the phone and email added as work values of PHONE and EMAIL fields.
+// At least one of phone and email must be non-empty, otherwise ErrEmptyContactKey is returned.
+func (c *Client) FindOrCreateContact(ctx context.Context, contact *ContactAdd, phone, email string) (int, bool, error) {
+	if contact == nil {
+		contact = new(ContactAdd)
+	}
+
+	hasPhone, hasEmail := NormalizePhone(phone) != "", NormalizeEmail(email) != ""
+	if !hasPhone && !hasEmail {
+		return 0, false, ErrEmptyContactKey
+	}
+
+	if hasPhone {
+		contacts, err := c.FindContactByPhone(ctx, phone)
+		if err != nil {
+			return 0, false, err
+		}
+		if len(contacts) > 0 {
+			return contacts[0].ID, false, nil
+		}
+	}
+
+	if hasEmail {
+		contacts, err := c.FindContactByEmail(ctx, email)
+		if err != nil {
+			return 0, false, err
+		}
+		if len(contacts) > 0 {
+			return contacts[0].ID, false, nil
+		}
+	}
+
+	account, err := c.GetAccount(ctx, &AccountRequestParams{With: []AccountWithType{AccountWithCustomFields}})
+	if err != nil {
+		return 0, false, err
+	}
+
+	if account == nil {
+		return 0, false, ErrEmptyResponseItems
+	}
+
+	add := *contact
+	add.CustomFields = append([]*UpdateCustomField(nil), contact.CustomFields...)
+	for _, v := range []struct {
+		ok    bool
+		value string
+		code  FieldCode
+	}{
+		{hasPhone, strings.TrimSpace(phone), PhoneFieldCode},
+		{hasEmail, strings.TrimSpace(email), EmailFieldCode},
+	} {
+		if !v.ok {
+			continue
+		}
+
+		field := contactFieldByCode(account, v.code)
+		if field == nil {
+			return 0, false, ErrUnknownCustomField
+		}
+
+		add.CustomFields = append(add.CustomFields, NewMultiTextField(field.ID, &MultiTextValue{Value: v.value, Subtype: WorkMultiTextSubtype}))
+	}
+
+	id, err := c.AddContact(ctx, &add)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
+
+func (c *Client) findContacts(ctx context.Context, query string, code FieldCode, match func(value string) bool) ([]*Contact, error) {
+	var contacts []*Contact
+	it := c.IterateContacts(&ContactRequestParams{Query: query})
+	for it.Next(ctx) {
+		contact := it.Contact()
+		for _, v := range contact.CustomFields.MultiText(code) {
+			if match(v.Value) {
+				contacts = append(contacts, contact)
+				break
+			}
+		}
+	}
+
+	return contacts, it.Err()
+}
+
+func contactFieldByCode(account *AccountResponse, code FieldCode) *CustomFieldInfo {
+	if account == nil {
+		return nil
+	}
+
+	for _, field := range account.Embedded.CustomFields.Contacts {
+		if strings.EqualFold(field.Code, string(code)) {
+			return field
+		}
+	}
+
+	return nil
+}
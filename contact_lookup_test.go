@@ -0,0 +1,70 @@
+package amocrm_test
+
+import (
+	"context"
+	"testing"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	for _, tt := range []struct {
+		phone string
+		want  string
+	}{
+		{"", ""},
+		{"no digits", ""},
+		{"+7 (900) 123-45-67", "+79001234567"},
+		{"8 900 123 45 67", "+79001234567"},
+		{"9001234567", "+79001234567"},
+		{"+8 900 123 45 67", "+89001234567"},
+		{"+1 202 555 0100", "+12025550100"},
+	} {
+		if got := amocrm.NormalizePhone(tt.phone); got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	for _, tt := range []struct {
+		email string
+		want  string
+	}{
+		{"", ""},
+		{"  ", ""},
+		{" Ivan@Example.COM ", "ivan@example.com"},
+		{"ivan@example.com", "ivan@example.com"},
+	} {
+		if got := amocrm.NormalizeEmail(tt.email); got != tt.want {
+			t.Errorf("NormalizeEmail(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestFindOrCreateContact(t *testing.T) {
+	_, c := newTestClient(t)
+	ctx := context.Background()
+
+	if _, _, err := c.FindOrCreateContact(ctx, nil, " ", ""); err != amocrm.ErrEmptyContactKey {
+		t.Fatalf("expected ErrEmptyContactKey, got %v", err)
+	}
+
+	id, created, err := c.FindOrCreateContact(ctx, &amocrm.ContactAdd{Name: "Ivan"}, "8 (900) 123-45-67", "Ivan@Example.com")
+	if err != nil || !created {
+		t.Fatalf("expected the contact to be created, got %v", err)
+	}
+
+	for _, key := range []struct {
+		phone string
+		email string
+	}{
+		{"+79001234567", ""},
+		{"", " ivan@example.com"},
+	} {
+		found, created, err := c.FindOrCreateContact(ctx, nil, key.phone, key.email)
+		if err != nil || created || found != id {
+			t.Fatalf("expected contact %d to be found by %+v, got %d, %v, %v", id, key, found, created, err)
+		}
+	}
+}
@@ -0,0 +1,539 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	ContactDuplicateKey string
+
+	MergeOption func(o *mergeOptions)
+
+	mergeOptions struct {
+		dryRun bool
+	}
+
+	// ContactMergeReport describes changes made by MergeContacts, or planned ones in the dry run mode.
+	// Duplicates themselves are kept, since API v2 is not able to delete contacts, and so are their notes:
+	// CopiedNotesID are copied to the primary contact, SkippedNotesID could not be added through the API.
+	ContactMergeReport struct {
+		PrimaryID      int
+		DuplicateID    []int
+		CustomFields   []*UpdateCustomField
+		CompanyID      int
+		LeadsID        []int
+		CopiedNotesID  []int
+		SkippedNotesID []int
+		TasksID        []int
+	}
+
+	mergedCustomField struct {
+		field   *UpdateCustomField
+		seen    map[string]bool
+		changed bool
+	}
+)
+
+const (
+	PhoneContactDuplicateKey ContactDuplicateKey = "phone"
+	EmailContactDuplicateKey ContactDuplicateKey = "email"
+	NameContactDuplicateKey  ContactDuplicateKey = "name"
+)
+
+var (
+	// notes of other kinds are created by amoCRM itself and could not be added through the API
+	copyableNoteKinds = map[NoteKind]bool{
+		CommonNoteKind:         true,
+		IncomingCallNoteKind:   true,
+		OutgoingCallNoteKind:   true,
+		ServiceMessageNoteKind: true,
+		IncomingSMSNoteKind:    true,
+		OutgoingSMSNoteKind:    true,
+	}
+)
+
+func WithDryRun() MergeOption {
+	return func(o *mergeOptions) {
+		o.dryRun = true
+	}
+}
+
+// FindDuplicateContacts scans contacts matching reqParams and groups the ones sharing a normalized phone, email or name,
+// contacts are compared by phones and emails, when no keys are given. Each group is ordered by contact id.
+// All scanned contacts are held in memory, so large accounts should be narrowed down by reqParams, nil scans all of them.
+func (c *Client) FindDuplicateContacts(ctx context.Context, reqParams *ContactRequestParams, keys ...ContactDuplicateKey) ([][]*Contact, error) {
+	if len(keys) == 0 {
+		keys = []ContactDuplicateKey{PhoneContactDuplicateKey, EmailContactDuplicateKey}
+	}
+
+	if err := c.validator.Var(keys, "dive,oneof=phone email name"); err != nil {
+		return nil, err
+	}
+
+	var contacts []*Contact
+	it := c.IterateContacts(reqParams)
+	for it.Next(ctx) {
+		contacts = append(contacts, it.Contact())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return groupDuplicateContacts(contacts, keys), nil
+}
+
+// MergeContacts moves everything, which could be moved through the API, from duplicates to the primary contact:
+// missing custom field values, company, leads and tasks. Notes could not be moved in API v2, so they are copied
+// and the originals stay on the duplicates; notes created by amoCRM itself or with invalid params are skipped.
+// All requests are built and validated before the first change is made.
+func (c *Client) MergeContacts(ctx context.Context, primaryID int, duplicateIDs []int, opts ...MergeOption) (*ContactMergeReport, error) {
+	o := mergeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if primaryID == 0 {
+		return nil, ErrEmptyEntityID
+	}
+
+	duplicateIDs = uniqueIDs(duplicateIDs, primaryID)
+	if err := c.validator.Var(duplicateIDs, "required,gt=0,dive,required"); err != nil {
+		return nil, err
+	}
+
+	primary, duplicates, err := c.getMergedContacts(ctx, primaryID, duplicateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := c.GetAccount(ctx, &AccountRequestParams{With: []AccountWithType{AccountWithCustomFields}})
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil {
+		return nil, ErrEmptyResponseItems
+	}
+
+	report := &ContactMergeReport{
+		PrimaryID:    primaryID,
+		DuplicateID:  duplicateIDs,
+		CustomFields: mergeCustomFields(account.Embedded.CustomFields.Contacts, primary, duplicates),
+	}
+
+	linked := make(map[int]bool, len(primary.Leads.ID))
+	for _, id := range primary.Leads.ID {
+		linked[id] = true
+	}
+
+	for _, duplicate := range duplicates {
+		if primary.Company.ID == 0 && report.CompanyID == 0 {
+			report.CompanyID = duplicate.Company.ID
+		}
+
+		for _, id := range duplicate.Leads.ID {
+			if !linked[id] {
+				linked[id] = true
+				report.LeadsID = append(report.LeadsID, id)
+			}
+		}
+	}
+
+	var tasks []*Task
+	taskIt := c.IterateTasks(&TaskRequestParams{Type: ContactTaskType, ElementID: duplicateIDs})
+	for taskIt.Next(ctx) {
+		tasks = append(tasks, taskIt.Task())
+		report.TasksID = append(report.TasksID, taskIt.Task().ID)
+	}
+	if err := taskIt.Err(); err != nil {
+		return nil, err
+	}
+
+	var notes []*NoteAdd
+	noteIt := c.IterateNotes(&NoteRequestParams{Type: ContactNoteType, ElementID: duplicateIDs})
+	for noteIt.Next(ctx) {
+		note := noteIt.Note()
+		if add := c.mergedNote(note, primaryID); add != nil {
+			notes = append(notes, add)
+			report.CopiedNotesID = append(report.CopiedNotesID, note.ID)
+		} else {
+			report.SkippedNotesID = append(report.SkippedNotesID, note.ID)
+		}
+	}
+	if err := noteIt.Err(); err != nil {
+		return nil, err
+	}
+
+	taskUpdates := make([]*TaskUpdate, 0, len(tasks))
+	for _, task := range tasks {
+		taskUpdates = append(taskUpdates, &TaskUpdate{
+			ID:          task.ID,
+			ElementID:   primaryID,
+			ElementType: ContactTaskElementType,
+			Text:        task.Text,
+			UpdatedAt:   nextUpdatedAt(task.UpdatedAt),
+		})
+	}
+
+	// every request is validated before the first change, so that an invalid one does not leave a half merged contact
+	contactUpdates := mergedContactUpdates(report, primary, duplicates)
+	if err := c.validator.Var(contactUpdates, "dive,required"); err != nil {
+		return nil, err
+	}
+
+	if err := c.validator.Var(taskUpdates, "dive,required"); err != nil {
+		return nil, err
+	}
+
+	if o.dryRun {
+		return report, nil
+	}
+
+	if len(contactUpdates) > 0 {
+		if err := firstBatchError(c.UpdateContacts(ctx, contactUpdates)); err != nil {
+			return report, err
+		}
+	}
+
+	if len(taskUpdates) > 0 {
+		if err := firstBatchError(c.UpdateTasks(ctx, taskUpdates)); err != nil {
+			return report, err
+		}
+	}
+
+	if len(notes) > 0 {
+		if err := firstBatchError(c.AddNotes(ctx, notes)); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// mergedNote returns the copy of the note for the primary contact, or nil when the note could not be added through the API:
+// it is created by amoCRM itself, or its params are not valid for a new note, like calls with zero duration.
+func (c *Client) mergedNote(note *Note, primaryID int) *NoteAdd {
+	if !copyableNoteKinds[note.NoteType] {
+		return nil
+	}
+
+	add := &NoteAdd{
+		ElementID:         primaryID,
+		ElementType:       ContactNoteElementType,
+		Text:              note.Text,
+		NoteType:          note.NoteType,
+		CreatedAt:         note.CreatedAt,
+		ResponsibleUserID: note.ResponsibleUserID,
+		CreatedBy:         note.CreatedBy,
+	}
+
+	if note.NoteType != CommonNoteKind && len(note.RawParameters) > 0 {
+		params, err := note.Params()
+		if err != nil {
+			return nil
+		}
+		add.Params = params
+	}
+
+	if err := c.validator.Struct(add); err != nil {
+		return nil
+	}
+
+	return add
+}
+
+func (c *Client) getMergedContacts(ctx context.Context, primaryID int, duplicateIDs []int) (*Contact, []*Contact, error) {
+	contacts, err := c.GetContacts(ctx, &ContactRequestParams{ID: append([]int{primaryID}, duplicateIDs...)})
+	if errors.Is(err, ErrEmptyResponseItems) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[int]*Contact, len(contacts))
+	for _, contact := range contacts {
+		byID[contact.ID] = contact
+	}
+
+	primary, ok := byID[primaryID]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+
+	duplicates := make([]*Contact, 0, len(duplicateIDs))
+	for _, id := range duplicateIDs {
+		duplicate, ok := byID[id]
+		if !ok {
+			return nil, nil, ErrNotFound
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return primary, duplicates, nil
+}
+
+// mergedContactUpdates returns the update of the primary contact and the ones unlinking leads from duplicates
+func mergedContactUpdates(report *ContactMergeReport, primary *Contact, duplicates []*Contact) []*ContactUpdate {
+	var updates []*ContactUpdate
+	if len(report.CustomFields) > 0 || report.CompanyID != 0 || len(report.LeadsID) > 0 {
+		update := &ContactUpdate{
+			ID:           primary.ID,
+			UpdatedAt:    nextUpdatedAt(primary.UpdatedAt),
+			CompanyID:    report.CompanyID,
+			CustomFields: report.CustomFields,
+		}
+
+		if len(report.LeadsID) > 0 {
+			// linked leads are replaced, so the ones already linked to the primary contact are sent too
+			for _, id := range append(append([]int(nil), primary.Leads.ID...), report.LeadsID...) {
+				update.LeadsID = append(update.LeadsID, strconv.Itoa(id))
+			}
+		}
+
+		updates = append(updates, update)
+	}
+
+	for _, duplicate := range duplicates {
+		if len(duplicate.Leads.ID) == 0 {
+			continue
+		}
+
+		updates = append(updates, &ContactUpdate{
+			ID:        duplicate.ID,
+			UpdatedAt: nextUpdatedAt(duplicate.UpdatedAt),
+			Unlink:    &Unlink{LeadsID: duplicate.Leads.ID},
+		})
+	}
+
+	return updates
+}
+
+func groupDuplicateContacts(contacts []*Contact, keys []ContactDuplicateKey) [][]*Contact {
+	// disjoint set over contact indexes, so that contacts sharing any key end up in the same group
+	parent := make([]int, len(contacts))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	first := make(map[string]int)
+	for i, contact := range contacts {
+		for _, key := range contactDuplicateKeys(contact, keys) {
+			if j, ok := first[key]; ok {
+				parent[find(i)] = find(j)
+			} else {
+				first[key] = i
+			}
+		}
+	}
+
+	groups := make(map[int][]*Contact)
+	for i, contact := range contacts {
+		root := find(i)
+		groups[root] = append(groups[root], contact)
+	}
+
+	var result [][]*Contact
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+
+		sort.Slice(group, func(i, j int) bool { return group[i].ID < group[j].ID })
+		result = append(result, group)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i][0].ID < result[j][0].ID })
+
+	return result
+}
+
+func contactDuplicateKeys(contact *Contact, keys []ContactDuplicateKey) []string {
+	var result []string
+	for _, key := range keys {
+		switch key {
+		case PhoneContactDuplicateKey:
+			for _, v := range contact.CustomFields.MultiText(PhoneFieldCode) {
+				if phone := NormalizePhone(v.Value); phone != "" {
+					result = append(result, "phone:"+phone)
+				}
+			}
+		case EmailContactDuplicateKey:
+			for _, v := range contact.CustomFields.MultiText(EmailFieldCode) {
+				if email := NormalizeEmail(v.Value); email != "" {
+					result = append(result, "email:"+email)
+				}
+			}
+		case NameContactDuplicateKey:
+			// word order is ignored, since the first and the last names are often swapped
+			words := strings.Fields(strings.ToLower(contact.Name))
+			if len(words) > 0 {
+				sort.Strings(words)
+				result = append(result, "name:"+strings.Join(words, " "))
+			}
+		}
+	}
+
+	return result
+}
+
+// mergeCustomFields returns fields to be updated on the primary contact: multiple value fields get the missing
+// values of duplicates, other fields are taken from the first duplicate, which has them, when the primary has none.
+func mergeCustomFields(infos map[string]*CustomFieldInfo, primary *Contact, duplicates []*Contact) []*UpdateCustomField {
+	byID := make(map[int]*CustomFieldInfo, len(infos))
+	for _, info := range infos {
+		byID[info.ID] = info
+	}
+
+	merged := make(map[int]*mergedCustomField)
+	var order []int
+	for _, duplicate := range duplicates {
+		for _, field := range duplicate.CustomFields {
+			if field == nil || len(field.Values) == 0 {
+				continue
+			}
+
+			info := byID[field.ID]
+			existing := primary.CustomFields.Field(FieldID(field.ID))
+			if info != nil && (info.FieldType == MultiTextCustomFieldType || info.FieldType == MultiSelectCustomFieldType) {
+				m, ok := merged[field.ID]
+				if !ok {
+					m = &mergedCustomField{field: &UpdateCustomField{ID: field.ID}, seen: make(map[string]bool)}
+					merged[field.ID] = m
+					order = append(order, field.ID)
+
+					// values of the field are replaced, so the existing ones are sent too
+					if existing != nil {
+						for _, v := range existing.Values {
+							m.add(info, field.Code, v)
+						}
+					}
+				}
+
+				for _, v := range field.Values {
+					if m.add(info, field.Code, v) {
+						m.changed = true
+					}
+				}
+
+				continue
+			}
+
+			if _, ok := merged[field.ID]; ok || (existing != nil && len(existing.Values) > 0) {
+				continue
+			}
+
+			m := &mergedCustomField{field: &UpdateCustomField{ID: field.ID}, changed: true}
+			for _, v := range field.Values {
+				m.field.Values = append(m.field.Values, updateCustomValue(info, v))
+			}
+			merged[field.ID] = m
+			order = append(order, field.ID)
+		}
+	}
+
+	var fields []*UpdateCustomField
+	for _, id := range order {
+		if m := merged[id]; m.changed {
+			fields = append(fields, m.field)
+		}
+	}
+
+	return fields
+}
+
+func (m *mergedCustomField) add(info *CustomFieldInfo, code string, v *CustomValue) bool {
+	var key string
+	switch {
+	case info.FieldType == MultiSelectCustomFieldType:
+		key = strconv.Itoa(v.Enum)
+	case strings.EqualFold(code, string(PhoneFieldCode)):
+		key = NormalizePhone(v.Value)
+	case strings.EqualFold(code, string(EmailFieldCode)):
+		key = NormalizeEmail(v.Value)
+	default:
+		key = strings.ToLower(strings.TrimSpace(v.Value))
+	}
+
+	if key == "" || m.seen[key] {
+		return false
+	}
+
+	m.seen[key] = true
+	m.field.Values = append(m.field.Values, updateCustomValue(info, v))
+
+	return true
+}
+
+// updateCustomValue converts a value from a response to the format accepted by updates
+func updateCustomValue(info *CustomFieldInfo, v *CustomValue) interface{} {
+	if info == nil {
+		return &UpdateCustomValue{Value: v.Value, Subtype: v.Subtype}
+	}
+
+	switch info.FieldType {
+	case SelectCustomFieldType, RadioButtonCustomFieldType:
+		return &UpdateCustomValue{Value: strconv.Itoa(v.Enum)}
+	case MultiSelectCustomFieldType:
+		return strconv.Itoa(v.Enum)
+	case MultiTextCustomFieldType:
+		enum := v.Subtype
+		if v.Enum != 0 {
+			enum = strconv.Itoa(v.Enum)
+		}
+		if enum == "" {
+			enum = string(OtherMultiTextSubtype)
+		}
+		return &UpdateCustomValue{Value: v.Value, Enum: enum}
+	case SmartAddressCustomFieldType:
+		return &UpdateCustomValue{Value: v.Value, Subtype: v.Subtype}
+	case LegalEntityCustomFieldType:
+		return map[string]json.RawMessage{"value": v.Raw}
+	case DateCustomFieldType, BirthDayCustomFieldType:
+		if date, ok := parseCustomFieldDate(v.Value); ok {
+			return &UpdateCustomValue{Value: date.Format(customFieldDateLayout)}
+		}
+	}
+
+	return &UpdateCustomValue{Value: v.Value}
+}
+
+func uniqueIDs(ids []int, exclude int) []int {
+	seen := map[int]bool{exclude: true}
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+func firstBatchError(results []*BatchResult, err error) error {
+	if !errors.Is(err, ErrBatchPartialFailure) {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+
+	return err
+}
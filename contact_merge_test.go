@@ -0,0 +1,156 @@
+package amocrm_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+func phoneField(phone string) *amocrm.UpdateCustomField {
+	return amocrm.NewMultiTextField(amocrmtest.PhoneCustomFieldID, &amocrm.MultiTextValue{Value: phone, Subtype: amocrm.MobileMultiTextSubtype})
+}
+
+func emailField(email string) *amocrm.UpdateCustomField {
+	return amocrm.NewMultiTextField(amocrmtest.EmailCustomFieldID, &amocrm.MultiTextValue{Value: email, Subtype: amocrm.WorkMultiTextSubtype})
+}
+
+func addTestContact(t *testing.T, c *amocrm.Client, contact *amocrm.ContactAdd) int {
+	t.Helper()
+
+	id, err := c.AddContact(context.Background(), contact)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+func TestFindDuplicateContacts(t *testing.T) {
+	_, c := newTestClient(t)
+
+	ivan := addTestContact(t, c, &amocrm.ContactAdd{Name: "Ivan Petrov", CustomFields: []*amocrm.UpdateCustomField{phoneField("+7 900 123-45-67")}})
+	petrov := addTestContact(t, c, &amocrm.ContactAdd{Name: "petrov  ivan", CustomFields: []*amocrm.UpdateCustomField{emailField("ivan@example.com")}})
+	other := addTestContact(t, c, &amocrm.ContactAdd{Name: "Other", CustomFields: []*amocrm.UpdateCustomField{phoneField("89001234567"), emailField(" IVAN@example.com")}})
+	addTestContact(t, c, &amocrm.ContactAdd{Name: "Solo"})
+
+	tests := []struct {
+		name   string
+		keys   []amocrm.ContactDuplicateKey
+		groups [][]int
+	}{
+		{name: "default keys", groups: [][]int{{ivan, petrov, other}}},
+		{name: "phone", keys: []amocrm.ContactDuplicateKey{amocrm.PhoneContactDuplicateKey}, groups: [][]int{{ivan, other}}},
+		{name: "email", keys: []amocrm.ContactDuplicateKey{amocrm.EmailContactDuplicateKey}, groups: [][]int{{petrov, other}}},
+		{name: "name", keys: []amocrm.ContactDuplicateKey{amocrm.NameContactDuplicateKey}, groups: [][]int{{ivan, petrov}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := c.FindDuplicateContacts(context.Background(), nil, tt.keys...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(groups) != len(tt.groups) {
+				t.Fatalf("expected %d groups, got %d", len(tt.groups), len(groups))
+			}
+
+			for i, group := range groups {
+				if len(group) != len(tt.groups[i]) {
+					t.Fatalf("expected group %v, got %d contacts", tt.groups[i], len(group))
+				}
+
+				for j, contact := range group {
+					if contact.ID != tt.groups[i][j] {
+						t.Fatalf("expected group %v, got contact %d at %d", tt.groups[i], contact.ID, j)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMergeContacts(t *testing.T) {
+	s, c := newTestClient(t)
+	ctx := context.Background()
+
+	primaryLead := addTestLead(t, c, "primary")
+	duplicateLead := addTestLead(t, c, "duplicate")
+	primary := addTestContact(t, c, &amocrm.ContactAdd{Name: "Ivan", LeadsID: []string{strconv.Itoa(primaryLead)}, CustomFields: []*amocrm.UpdateCustomField{phoneField("+79001234567")}})
+	duplicate := addTestContact(t, c, &amocrm.ContactAdd{Name: "Ivan", LeadsID: []string{strconv.Itoa(duplicateLead)}, CustomFields: []*amocrm.UpdateCustomField{emailField("ivan@example.com")}})
+
+	taskID, err := c.AddTask(ctx, &amocrm.TaskAdd{ElementID: duplicate, ElementType: amocrm.ContactTaskElementType, TaskType: 1, Text: "call", CompleteTill: amocrm.NewTimestamp(time.Now().Add(time.Hour))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commonID, err := c.AddNote(ctx, amocrm.NewCommonNote(amocrm.ContactNoteElementType, duplicate, "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// missed calls are logged by telephony with zero duration, which is not accepted for a new note
+	missedID := s.AddNote(&amocrm.Note{
+		ElementID:     duplicate,
+		ElementType:   amocrm.ContactNoteElementType,
+		NoteType:      amocrm.IncomingCallNoteKind,
+		Text:          "missed call",
+		RawParameters: []byte(`{"UNIQ":"1","LINK":"-","PHONE":"+79001234567","DURATION":0,"SRC":"pbx","call_status":6}`),
+	})
+
+	report, err := c.MergeContacts(ctx, primary, []int{duplicate}, amocrm.WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.CustomFields) != 1 || len(report.LeadsID) != 1 || report.LeadsID[0] != duplicateLead || len(report.TasksID) != 1 || report.TasksID[0] != taskID {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if len(report.CopiedNotesID) != 1 || report.CopiedNotesID[0] != commonID || len(report.SkippedNotesID) != 1 || report.SkippedNotesID[0] != missedID {
+		t.Fatalf("unexpected notes in report: copied %v, skipped %v", report.CopiedNotesID, report.SkippedNotesID)
+	}
+
+	if task := s.Task(taskID); task.ElementID != duplicate {
+		t.Fatal("dry run moved the task")
+	}
+
+	if contact := s.Contact(primary); len(contact.Leads.ID) != 1 {
+		t.Fatal("dry run updated the primary contact")
+	}
+
+	if _, err := c.MergeContacts(ctx, primary, []int{duplicate}); err != nil {
+		t.Fatal(err)
+	}
+
+	merged := s.Contact(primary)
+	if len(merged.Leads.ID) != 2 || len(merged.CustomFields.MultiText(amocrm.EmailFieldCode)) != 1 {
+		t.Fatalf("unexpected primary contact: %+v", merged)
+	}
+
+	if len(s.Contact(duplicate).Leads.ID) != 0 || s.Task(taskID).ElementID != primary {
+		t.Fatal("leads and tasks are not moved to the primary contact")
+	}
+
+	notes, err := c.GetNotes(ctx, &amocrm.NoteRequestParams{Type: amocrm.ContactNoteType, ElementID: []int{primary}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(notes) != 1 || notes[0].Text != "hello" {
+		t.Fatalf("expected the common note to be copied, got %+v", notes)
+	}
+
+	notes, err = c.GetNotes(ctx, &amocrm.NoteRequestParams{Type: amocrm.ContactNoteType, ElementID: []int{duplicate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(notes) != 2 {
+		t.Fatalf("expected the original notes to stay on the duplicate, got %d", len(notes))
+	}
+}
@@ -1,17 +1,28 @@
 package amocrm
 
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type (
+	CustomFields []*CustomField
+
 	CustomField struct {
 		ID       int            `json:"id" validate:"required"`
 		Name     string         `json:"name" validate:"required"`
+		Code     string         `json:"code,omitempty" validate:"omitempty"`
 		Values   []*CustomValue `json:"values" validate:"required,dive,required"`
 		IsSystem bool           `json:"is_system" validate:"omitempty"`
 	}
 
 	CustomValue struct {
-		Value   string `json:"value" validate:"required"`
-		Enum    int    `json:"enum,omitempty" validate:"omitempty"`
-		Subtype string `json:"subtype,omitempty" validate:"omitempty"`
+		Value   string          `json:"value" validate:"required"`
+		Enum    int             `json:"enum,omitempty" validate:"omitempty"`
+		Subtype string          `json:"subtype,omitempty" validate:"omitempty"`
+		Raw     json.RawMessage `json:"-" validate:"omitempty"`
 	}
 
 	UpdateCustomField struct {
@@ -21,9 +32,49 @@ type (
 
 	UpdateCustomValue struct {
 		Value   string `json:"value" validate:"required"`
+		Enum    string `json:"enum,omitempty" validate:"omitempty"`
 		Subtype string `json:"subtype,omitempty" validate:"omitempty"`
 	}
 
+	UpdateLegalEntityValue struct {
+		Value *LegalEntity `json:"value" validate:"required"`
+	}
+
+	CustomFieldKey interface {
+		matchCustomField(field *CustomField) bool
+	}
+
+	FieldID int
+
+	FieldCode string
+
+	MultiTextSubtype string
+
+	MultiTextValue struct {
+		Value   string           `validate:"required"`
+		Subtype MultiTextSubtype `validate:"omitempty"`
+		EnumID  int              `validate:"omitempty"`
+	}
+
+	SmartAddressSubtype string
+
+	SmartAddress struct {
+		AddressLine1 string `validate:"omitempty"`
+		AddressLine2 string `validate:"omitempty"`
+		City         string `validate:"omitempty"`
+		State        string `validate:"omitempty"`
+		Zip          string `validate:"omitempty"`
+		Country      string `validate:"omitempty"`
+	}
+
+	LegalEntity struct {
+		Name                      string `json:"name" validate:"required"`
+		EntityType                int    `json:"entity_type,omitempty" validate:"omitempty"`
+		VatID                     string `json:"vat_id,omitempty" validate:"omitempty"`
+		TaxRegistrationReasonCode string `json:"kpp,omitempty" validate:"omitempty"`
+		Address                   string `json:"address,omitempty" validate:"omitempty"`
+	}
+
 	CustomFieldType int
 
 	CustomFieldInfo struct {
@@ -62,3 +113,322 @@ const (
 	ItemsCustomFieldType
 	OrgLegalNameCustomFieldType
 )
+
+const (
+	WorkMultiTextSubtype       MultiTextSubtype = "WORK"
+	WorkDirectMultiTextSubtype MultiTextSubtype = "WORKDD"
+	MobileMultiTextSubtype     MultiTextSubtype = "MOB"
+	FaxMultiTextSubtype        MultiTextSubtype = "FAX"
+	HomeMultiTextSubtype       MultiTextSubtype = "HOME"
+	PrivateMultiTextSubtype    MultiTextSubtype = "PRIV"
+	OtherMultiTextSubtype      MultiTextSubtype = "OTHER"
+
+	AddressLine1SmartAddressSubtype SmartAddressSubtype = "address_line_1"
+	AddressLine2SmartAddressSubtype SmartAddressSubtype = "address_line_2"
+	CitySmartAddressSubtype         SmartAddressSubtype = "city"
+	StateSmartAddressSubtype        SmartAddressSubtype = "state"
+	ZipSmartAddressSubtype          SmartAddressSubtype = "zip"
+	CountrySmartAddressSubtype      SmartAddressSubtype = "country"
+
+	PhoneFieldCode FieldCode = "PHONE"
+	EmailFieldCode FieldCode = "EMAIL"
+
+	customFieldDateLayout = "2006-01-02"
+)
+
+var (
+	customFieldDateLayouts = []string{
+		"2006-01-02 15:04:05",
+		customFieldDateLayout,
+		"02.01.2006",
+		time.RFC3339,
+	}
+)
+
+func (v *CustomValue) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Value   json.RawMessage `json:"value"`
+		Enum    looseInt        `json:"enum"`
+		Subtype string          `json:"subtype"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	// value is a string for most of the field types, but legal entities are objects and some values are numbers
+	var value string
+	if err := json.Unmarshal(raw.Value, &value); err != nil {
+		value = string(raw.Value)
+	}
+
+	v.Value = value
+	v.Enum = int(raw.Enum)
+	v.Subtype = raw.Subtype
+	v.Raw = raw.Value
+
+	return nil
+}
+
+func (id FieldID) matchCustomField(field *CustomField) bool {
+	return field.ID == int(id)
+}
+
+func (code FieldCode) matchCustomField(field *CustomField) bool {
+	return strings.EqualFold(field.Code, string(code))
+}
+
+func NewTextField(id int, value string) *UpdateCustomField {
+	return &UpdateCustomField{ID: id, Values: []interface{}{&UpdateCustomValue{Value: value}}}
+}
+
+func NewNumericField(id int, value float64) *UpdateCustomField {
+	return &UpdateCustomField{ID: id, Values: []interface{}{&UpdateCustomValue{Value: strconv.FormatFloat(value, 'f', -1, 64)}}}
+}
+
+func NewCheckboxField(id int, checked bool) *UpdateCustomField {
+	value := "0"
+	if checked {
+		value = "1"
+	}
+
+	return &UpdateCustomField{ID: id, Values: []interface{}{&UpdateCustomValue{Value: value}}}
+}
+
+func NewSelectField(id int, enumID int) *UpdateCustomField {
+	return &UpdateCustomField{ID: id, Values: []interface{}{&UpdateCustomValue{Value: strconv.Itoa(enumID)}}}
+}
+
+func NewRadioButtonField(id int, enumID int) *UpdateCustomField {
+	return NewSelectField(id, enumID)
+}
+
+func NewMultiSelectField(id int, enumIDs ...int) *UpdateCustomField {
+	values := make([]interface{}, 0, len(enumIDs))
+	for _, enumID := range enumIDs {
+		values = append(values, strconv.Itoa(enumID))
+	}
+
+	return &UpdateCustomField{ID: id, Values: values}
+}
+
+func NewDateField(id int, date time.Time) *UpdateCustomField {
+	return &UpdateCustomField{ID: id, Values: []interface{}{&UpdateCustomValue{Value: date.Format(customFieldDateLayout)}}}
+}
+
+func NewBirthDayField(id int, date time.Time) *UpdateCustomField {
+	return NewDateField(id, date)
+}
+
+func NewURLField(id int, url string) *UpdateCustomField {
+	return NewTextField(id, url)
+}
+
+func NewTextAreaField(id int, value string) *UpdateCustomField {
+	return NewTextField(id, value)
+}
+
+func NewStreetAddressField(id int, address string) *UpdateCustomField {
+	return NewTextField(id, address)
+}
+
+func NewMultiTextField(id int, values ...*MultiTextValue) *UpdateCustomField {
+	field := &UpdateCustomField{ID: id, Values: make([]interface{}, 0, len(values))}
+	for _, v := range values {
+		subtype := v.Subtype
+		if subtype == "" {
+			subtype = OtherMultiTextSubtype
+		}
+
+		field.Values = append(field.Values, &UpdateCustomValue{Value: v.Value, Enum: string(subtype)})
+	}
+
+	return field
+}
+
+func NewSmartAddressField(id int, address *SmartAddress) *UpdateCustomField {
+	field := &UpdateCustomField{ID: id}
+	for _, part := range []struct {
+		value   string
+		subtype SmartAddressSubtype
+	}{
+		{address.AddressLine1, AddressLine1SmartAddressSubtype},
+		{address.AddressLine2, AddressLine2SmartAddressSubtype},
+		{address.City, CitySmartAddressSubtype},
+		{address.State, StateSmartAddressSubtype},
+		{address.Zip, ZipSmartAddressSubtype},
+		{address.Country, CountrySmartAddressSubtype},
+	} {
+		if part.value != "" {
+			field.Values = append(field.Values, &UpdateCustomValue{Value: part.value, Subtype: string(part.subtype)})
+		}
+	}
+
+	return field
+}
+
+func NewLegalEntityField(id int, entities ...*LegalEntity) *UpdateCustomField {
+	field := &UpdateCustomField{ID: id, Values: make([]interface{}, 0, len(entities))}
+	for _, entity := range entities {
+		field.Values = append(field.Values, &UpdateLegalEntityValue{Value: entity})
+	}
+
+	return field
+}
+
+func (cf CustomFields) Field(key CustomFieldKey) *CustomField {
+	for _, field := range cf {
+		if field != nil && key.matchCustomField(field) {
+			return field
+		}
+	}
+
+	return nil
+}
+
+func (cf CustomFields) Values(key CustomFieldKey) []*CustomValue {
+	field := cf.Field(key)
+	if field == nil {
+		return nil
+	}
+
+	return field.Values
+}
+
+func (cf CustomFields) Text(key CustomFieldKey) string {
+	values := cf.Values(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0].Value
+}
+
+func (cf CustomFields) URL(key CustomFieldKey) string {
+	return cf.Text(key)
+}
+
+func (cf CustomFields) Numeric(key CustomFieldKey) (float64, bool) {
+	text := cf.Text(key)
+	if text == "" {
+		return 0, false
+	}
+
+	n, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+func (cf CustomFields) Checkbox(key CustomFieldKey) bool {
+	switch cf.Text(key) {
+	case "1", "true":
+		return true
+	default:
+		return false
+	}
+}
+
+func (cf CustomFields) Enum(key CustomFieldKey) (int, bool) {
+	enums := cf.Enums(key)
+	if len(enums) == 0 {
+		return 0, false
+	}
+
+	return enums[0], true
+}
+
+func (cf CustomFields) Enums(key CustomFieldKey) []int {
+	values := cf.Values(key)
+	enums := make([]int, 0, len(values))
+	for _, v := range values {
+		if v.Enum != 0 {
+			enums = append(enums, v.Enum)
+		}
+	}
+
+	return enums
+}
+
+func (cf CustomFields) Date(key CustomFieldKey) (time.Time, bool) {
+	return parseCustomFieldDate(cf.Text(key))
+}
+
+func (cf CustomFields) BirthDay(key CustomFieldKey) (time.Time, bool) {
+	return cf.Date(key)
+}
+
+func (cf CustomFields) MultiText(key CustomFieldKey) []*MultiTextValue {
+	values := cf.Values(key)
+	result := make([]*MultiTextValue, 0, len(values))
+	for _, v := range values {
+		result = append(result, &MultiTextValue{
+			Value:   v.Value,
+			Subtype: MultiTextSubtype(v.Subtype),
+			EnumID:  v.Enum,
+		})
+	}
+
+	return result
+}
+
+func (cf CustomFields) SmartAddress(key CustomFieldKey) *SmartAddress {
+	values := cf.Values(key)
+	if len(values) == 0 {
+		return nil
+	}
+
+	address := new(SmartAddress)
+	for _, v := range values {
+		switch SmartAddressSubtype(v.Subtype) {
+		case AddressLine1SmartAddressSubtype:
+			address.AddressLine1 = v.Value
+		case AddressLine2SmartAddressSubtype:
+			address.AddressLine2 = v.Value
+		case CitySmartAddressSubtype:
+			address.City = v.Value
+		case StateSmartAddressSubtype:
+			address.State = v.Value
+		case ZipSmartAddressSubtype:
+			address.Zip = v.Value
+		case CountrySmartAddressSubtype:
+			address.Country = v.Value
+		}
+	}
+
+	return address
+}
+
+func (cf CustomFields) LegalEntities(key CustomFieldKey) []*LegalEntity {
+	values := cf.Values(key)
+	entities := make([]*LegalEntity, 0, len(values))
+	for _, v := range values {
+		entity := new(LegalEntity)
+		if err := json.Unmarshal(v.Raw, entity); err != nil {
+			continue
+		}
+		entities = append(entities, entity)
+	}
+
+	return entities
+}
+
+func parseCustomFieldDate(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(n, 0), true
+	}
+
+	for _, layout := range customFieldDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
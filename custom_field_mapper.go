@@ -0,0 +1,451 @@
+package amocrm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// CustomFieldMapper maps custom fields into structs tagged with `amocrm:"id=123"` or `amocrm:"code=PHONE,subtype=WORK"`.
+	// Field codes and enum subtypes are resolved with the account custom fields metadata.
+	CustomFieldMapper struct {
+		byID   map[int]*CustomFieldInfo
+		byCode map[string]*CustomFieldInfo
+	}
+
+	customFieldTag struct {
+		id      int
+		code    string
+		subtype string
+		enum    bool
+	}
+
+	customFieldRef struct {
+		id   int
+		code string
+	}
+)
+
+const (
+	customFieldTagName = "amocrm"
+)
+
+var (
+	timeType             = reflect.TypeOf(time.Time{})
+	smartAddressType     = reflect.TypeOf(SmartAddress{})
+	legalEntitiesType    = reflect.TypeOf([]*LegalEntity{})
+	multiTextValuesType  = reflect.TypeOf([]*MultiTextValue{})
+	smartAddressSubtypes = map[SmartAddressSubtype]bool{
+		AddressLine1SmartAddressSubtype: true,
+		AddressLine2SmartAddressSubtype: true,
+		CitySmartAddressSubtype:         true,
+		StateSmartAddressSubtype:        true,
+		ZipSmartAddressSubtype:          true,
+		CountrySmartAddressSubtype:      true,
+	}
+)
+
+// NewCustomFieldMapper builds a mapper from the custom fields metadata of an entity, e.g. AccountResponse.Embedded.CustomFields.Leads.
+func NewCustomFieldMapper(fields map[string]*CustomFieldInfo) *CustomFieldMapper {
+	m := &CustomFieldMapper{
+		byID:   make(map[int]*CustomFieldInfo, len(fields)),
+		byCode: make(map[string]*CustomFieldInfo, len(fields)),
+	}
+
+	for _, f := range fields {
+		if f == nil {
+			continue
+		}
+
+		m.byID[f.ID] = f
+		if f.Code != "" {
+			m.byCode[strings.ToUpper(f.Code)] = f
+		}
+	}
+
+	return m
+}
+
+// DecodeCustomFields stores custom field values into the tagged fields of the struct pointed to by dst.
+// Without the account metadata codes are matched only against CustomField.Code.
+func DecodeCustomFields(fields CustomFields, dst interface{}) error {
+	return (*CustomFieldMapper)(nil).Decode(fields, dst)
+}
+
+// EncodeCustomFields builds custom fields for add and update requests from the tagged fields of src.
+// Without the account metadata every tag must reference a field by id.
+func EncodeCustomFields(src interface{}) ([]*UpdateCustomField, error) {
+	return (*CustomFieldMapper)(nil).Encode(src)
+}
+
+func (m *CustomFieldMapper) Decode(fields CustomFields, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidCustomFieldTarget
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag, ok, err := parseCustomFieldTag(sf)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		ref := m.ref(tag)
+		field := fields.Field(ref)
+		if field == nil {
+			continue
+		}
+
+		tag.enum = tag.enum || m.isEnum(field.ID)
+		if err := m.decodeValue(rv.Field(i), field.ID, tag, m.values(field, tag.subtype)); err != nil {
+			return fmt.Errorf("%s: %w", sf.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Encode skips zero values, so it never clears fields in amoCRM.
+func (m *CustomFieldMapper) Encode(src interface{}) ([]*UpdateCustomField, error) {
+	rv := reflect.ValueOf(src)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrInvalidCustomFieldTarget
+	}
+
+	var result []*UpdateCustomField
+	byID := make(map[int]*UpdateCustomField)
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag, ok, err := parseCustomFieldTag(sf)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+
+		id, err := m.resolveID(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sf.Name, err)
+		}
+
+		tag.enum = tag.enum || m.isEnum(id)
+		values, err := m.encodeValue(fv, id, tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sf.Name, err)
+		}
+
+		// multiselect fields take a plain list of enum ids, while select and radio button fields take a value object
+		if tag.enum && !m.isMultiSelect(id, fv) {
+			for j, v := range values {
+				enumID, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s: %w", sf.Name, ErrUnsupportedCustomFieldKind)
+				}
+				values[j] = &UpdateCustomValue{Value: enumID}
+			}
+		}
+
+		// several struct fields could reference the same custom field with different subtypes, e.g. work and mobile phones
+		if field, ok := byID[id]; ok {
+			field.Values = append(field.Values, values...)
+			continue
+		}
+
+		field := &UpdateCustomField{ID: id, Values: values}
+		byID[id] = field
+		result = append(result, field)
+	}
+
+	return result, nil
+}
+
+func parseCustomFieldTag(sf reflect.StructField) (*customFieldTag, bool, error) {
+	raw, ok := sf.Tag.Lookup(customFieldTagName)
+	if !ok || raw == "-" {
+		return nil, false, nil
+	}
+
+	// unexported fields could be neither read nor set through reflection
+	if sf.PkgPath != "" {
+		return nil, false, fmt.Errorf("%s: %w", sf.Name, ErrInvalidCustomFieldTag)
+	}
+
+	tag := new(customFieldTag)
+	for _, part := range strings.Split(raw, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		switch {
+		case kv[0] == "enum" && len(kv) == 1:
+			tag.enum = true
+		case kv[0] == "id" && len(kv) == 2:
+			id, err := strconv.Atoi(kv[1])
+			if err != nil || id <= 0 {
+				return nil, false, fmt.Errorf("%s: %w", sf.Name, ErrInvalidCustomFieldTag)
+			}
+			tag.id = id
+		case kv[0] == "code" && len(kv) == 2 && kv[1] != "":
+			tag.code = kv[1]
+		case kv[0] == "subtype" && len(kv) == 2 && kv[1] != "":
+			tag.subtype = kv[1]
+		default:
+			return nil, false, fmt.Errorf("%s: %w", sf.Name, ErrInvalidCustomFieldTag)
+		}
+	}
+
+	if tag.id == 0 && tag.code == "" {
+		return nil, false, fmt.Errorf("%s: %w", sf.Name, ErrInvalidCustomFieldTag)
+	}
+
+	return tag, true, nil
+}
+
+func (r *customFieldRef) matchCustomField(field *CustomField) bool {
+	return (r.id != 0 && field.ID == r.id) || (r.code != "" && strings.EqualFold(field.Code, r.code))
+}
+
+func (m *CustomFieldMapper) ref(tag *customFieldTag) *customFieldRef {
+	ref := &customFieldRef{id: tag.id, code: tag.code}
+	if ref.id == 0 {
+		if info := m.info(0, tag.code); info != nil {
+			ref.id = info.ID
+		}
+	}
+
+	return ref
+}
+
+func (m *CustomFieldMapper) resolveID(tag *customFieldTag) (int, error) {
+	if tag.id != 0 {
+		return tag.id, nil
+	}
+
+	info := m.info(0, tag.code)
+	if info == nil {
+		return 0, ErrUnknownCustomField
+	}
+
+	return info.ID, nil
+}
+
+func (m *CustomFieldMapper) info(id int, code string) *CustomFieldInfo {
+	if m == nil {
+		return nil
+	}
+
+	if code != "" {
+		return m.byCode[strings.ToUpper(code)]
+	}
+
+	return m.byID[id]
+}
+
+func (m *CustomFieldMapper) isEnum(id int) bool {
+	info := m.info(id, "")
+	if info == nil {
+		return false
+	}
+
+	switch info.FieldType {
+	case SelectCustomFieldType, MultiSelectCustomFieldType, RadioButtonCustomFieldType:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *CustomFieldMapper) isMultiSelect(id int, fv reflect.Value) bool {
+	if info := m.info(id, ""); info != nil {
+		return info.FieldType == MultiSelectCustomFieldType
+	}
+
+	return fv.Kind() == reflect.Slice
+}
+
+func (m *CustomFieldMapper) isSmartAddress(id int, subtype string) bool {
+	if info := m.info(id, ""); info != nil {
+		return info.FieldType == SmartAddressCustomFieldType
+	}
+
+	return smartAddressSubtypes[SmartAddressSubtype(subtype)]
+}
+
+// values filters field values by subtype, multitext values in responses reference subtypes only by enum id
+func (m *CustomFieldMapper) values(field *CustomField, subtype string) []*CustomValue {
+	if subtype == "" {
+		return field.Values
+	}
+
+	info := m.info(field.ID, "")
+
+	values := make([]*CustomValue, 0, len(field.Values))
+	for _, v := range field.Values {
+		if strings.EqualFold(v.Subtype, subtype) {
+			values = append(values, v)
+			continue
+		}
+
+		if info != nil && v.Enum != 0 && strings.EqualFold(info.Enums[strconv.Itoa(v.Enum)], subtype) {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func (m *CustomFieldMapper) decodeValue(fv reflect.Value, id int, tag *customFieldTag, values []*CustomValue) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	switch fv.Type() {
+	case timeType:
+		t, ok := parseCustomFieldDate(values[0].Value)
+		if !ok {
+			return ErrUnsupportedCustomFieldKind
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	case smartAddressType:
+		fv.Set(reflect.ValueOf(*CustomFields{{ID: id, Values: values}}.SmartAddress(FieldID(id))))
+		return nil
+	case legalEntitiesType:
+		fv.Set(reflect.ValueOf(CustomFields{{ID: id, Values: values}}.LegalEntities(FieldID(id))))
+		return nil
+	case multiTextValuesType:
+		fv.Set(reflect.ValueOf(CustomFields{{ID: id, Values: values}}.MultiText(FieldID(id))))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(fv.Type().Elem())
+		if err := m.decodeValue(elem.Elem(), id, tag, values); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	case reflect.Slice:
+		slice := reflect.MakeSlice(fv.Type(), 0, len(values))
+		for _, v := range values {
+			elem := reflect.New(fv.Type().Elem()).Elem()
+			if err := m.decodeValue(elem, id, tag, []*CustomValue{v}); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	return decodeScalar(fv, tag, values[0])
+}
+
+func decodeScalar(fv reflect.Value, tag *customFieldTag, v *CustomValue) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(v.Value)
+	case reflect.Bool:
+		fv.SetBool(v.Value == "1" || v.Value == "true")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if tag.enum {
+			fv.SetInt(int64(v.Enum))
+			return nil
+		}
+
+		n, err := strconv.ParseInt(v.Value, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(strings.Replace(v.Value, ",", ".", 1), 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	default:
+		return ErrUnsupportedCustomFieldKind
+	}
+
+	return nil
+}
+
+func (m *CustomFieldMapper) encodeValue(fv reflect.Value, id int, tag *customFieldTag) ([]interface{}, error) {
+	switch fv.Type() {
+	case timeType:
+		return NewDateField(id, fv.Interface().(time.Time)).Values, nil
+	case smartAddressType:
+		address := fv.Interface().(SmartAddress)
+		return NewSmartAddressField(id, &address).Values, nil
+	case legalEntitiesType:
+		return NewLegalEntityField(id, fv.Interface().([]*LegalEntity)...).Values, nil
+	case multiTextValuesType:
+		return NewMultiTextField(id, fv.Interface().([]*MultiTextValue)...).Values, nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Ptr:
+		return m.encodeValue(fv.Elem(), id, tag)
+	case reflect.Slice:
+		values := make([]interface{}, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v, err := m.encodeValue(fv.Index(i), id, tag)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v...)
+		}
+		return values, nil
+	}
+
+	var value string
+	switch fv.Kind() {
+	case reflect.String:
+		value = fv.String()
+	case reflect.Bool:
+		value = "0"
+		if fv.Bool() {
+			value = "1"
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = strconv.FormatInt(fv.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		value = strconv.FormatFloat(fv.Float(), 'f', -1, 64)
+	default:
+		return nil, ErrUnsupportedCustomFieldKind
+	}
+
+	if tag.enum {
+		return []interface{}{value}, nil
+	}
+
+	v := &UpdateCustomValue{Value: value}
+	if tag.subtype != "" {
+		if m.isSmartAddress(id, tag.subtype) {
+			v.Subtype = tag.subtype
+		} else {
+			v.Enum = tag.subtype
+		}
+	}
+
+	return []interface{}{v}, nil
+}
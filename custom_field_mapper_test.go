@@ -0,0 +1,212 @@
+package amocrm_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+type (
+	mappedLead struct {
+		Budget       int      `amocrm:"id=4"`
+		Source       int      `amocrm:"id=2"`
+		Tags         []int    `amocrm:"id=3"`
+		WorkPhone    string   `amocrm:"code=PHONE,subtype=WORK"`
+		MobilePhones []string `amocrm:"code=PHONE,subtype=MOB"`
+		Paid         bool     `amocrm:"id=5"`
+		Comment      string   `amocrm:"-"`
+	}
+
+	unmappedLead struct {
+		Budget int      `amocrm:"id=4"`
+		Source int      `amocrm:"id=2,enum"`
+		Phones []string `amocrm:"code=PHONE,subtype=MOB"`
+	}
+
+	missingFieldLead struct {
+		Name string `amocrm:"id=99"`
+	}
+
+	unexportedFieldLead struct {
+		budget int `amocrm:"id=4"`
+	}
+
+	invalidTagLead struct {
+		Budget int `amocrm:"id=budget"`
+	}
+
+	unsupportedKindLead struct {
+		Budget map[string]string `amocrm:"id=4"`
+	}
+
+	unknownCodeLead struct {
+		Phone string `amocrm:"code=PHONE"`
+	}
+
+	enumDateLead struct {
+		PaidAt time.Time `amocrm:"id=6,enum"`
+	}
+)
+
+func testCustomFieldMapper() *amocrm.CustomFieldMapper {
+	return amocrm.NewCustomFieldMapper(map[string]*amocrm.CustomFieldInfo{
+		"1": {ID: 1, Name: "Phone", Code: "PHONE", FieldType: amocrm.MultiTextCustomFieldType, Enums: map[string]string{"101": "WORK", "102": "MOB"}},
+		"2": {ID: 2, Name: "Source", FieldType: amocrm.SelectCustomFieldType, Enums: map[string]string{"201": "Web", "202": "Call"}},
+		"3": {ID: 3, Name: "Tags", FieldType: amocrm.MultiSelectCustomFieldType, Enums: map[string]string{"301": "New", "302": "VIP"}},
+		"4": {ID: 4, Name: "Budget", FieldType: amocrm.NumericCustomFieldType},
+		"5": {ID: 5, Name: "Paid", FieldType: amocrm.CheckboxCustomFieldType},
+	})
+}
+
+func TestCustomFieldMapperDecode(t *testing.T) {
+	fields := amocrm.CustomFields{
+		{ID: 1, Name: "Phone", Code: "PHONE", Values: []*amocrm.CustomValue{{Value: "+71", Enum: 101}, {Value: "+72", Enum: 102}, {Value: "+73", Subtype: "MOB"}}},
+		{ID: 2, Name: "Source", Values: []*amocrm.CustomValue{{Value: "Web", Enum: 201}}},
+		{ID: 3, Name: "Tags", Values: []*amocrm.CustomValue{{Value: "New", Enum: 301}, {Value: "VIP", Enum: 302}}},
+		{ID: 4, Name: "Budget", Values: []*amocrm.CustomValue{{Value: "1500"}}},
+		{ID: 5, Name: "Paid", Values: []*amocrm.CustomValue{{Value: "1"}}},
+	}
+
+	tests := []struct {
+		name   string
+		mapper *amocrm.CustomFieldMapper
+		dst    interface{}
+		want   interface{}
+		err    error
+	}{
+		{
+			name:   "with metadata",
+			mapper: testCustomFieldMapper(),
+			dst:    &mappedLead{Comment: "keep"},
+			want:   &mappedLead{Budget: 1500, Source: 201, Tags: []int{301, 302}, WorkPhone: "+71", MobilePhones: []string{"+72", "+73"}, Paid: true, Comment: "keep"},
+		},
+		{
+			name: "without metadata",
+			dst:  &unmappedLead{},
+			want: &unmappedLead{Budget: 1500, Source: 201, Phones: []string{"+73"}},
+		},
+		{
+			name: "missing field",
+			dst:  &missingFieldLead{Name: "keep"},
+			want: &missingFieldLead{Name: "keep"},
+		},
+		{
+			name: "unexported field",
+			dst:  &unexportedFieldLead{},
+			err:  amocrm.ErrInvalidCustomFieldTag,
+		},
+		{
+			name: "invalid tag",
+			dst:  &invalidTagLead{},
+			err:  amocrm.ErrInvalidCustomFieldTag,
+		},
+		{
+			name: "unsupported kind",
+			dst:  &unsupportedKindLead{},
+			err:  amocrm.ErrUnsupportedCustomFieldKind,
+		},
+		{
+			name: "not a pointer",
+			dst:  mappedLead{},
+			err:  amocrm.ErrInvalidCustomFieldTarget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mapper.Decode(fields, tt.dst)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(tt.dst, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, tt.dst)
+			}
+		})
+	}
+}
+
+func TestCustomFieldMapperEncode(t *testing.T) {
+	tests := []struct {
+		name   string
+		mapper *amocrm.CustomFieldMapper
+		src    interface{}
+		want   string
+		err    error
+	}{
+		{
+			name:   "with metadata",
+			mapper: testCustomFieldMapper(),
+			src:    &mappedLead{Budget: 1500, Source: 201, Tags: []int{301, 302}, WorkPhone: "+71", MobilePhones: []string{"+72"}, Paid: true, Comment: "skip"},
+			want: `[{"id":4,"values":[{"value":"1500"}]},{"id":2,"values":[{"value":"201"}]},{"id":3,"values":["301","302"]},` +
+				`{"id":1,"values":[{"value":"+71","enum":"WORK"},{"value":"+72","enum":"MOB"}]},{"id":5,"values":[{"value":"1"}]}]`,
+		},
+		{
+			name: "without metadata",
+			src:  unmappedLead{Budget: 1500, Source: 201},
+			want: `[{"id":4,"values":[{"value":"1500"}]},{"id":2,"values":[{"value":"201"}]}]`,
+		},
+		{
+			name:   "zero values",
+			mapper: testCustomFieldMapper(),
+			src:    &mappedLead{},
+			want:   `null`,
+		},
+		{
+			name: "unknown code",
+			src:  &unknownCodeLead{Phone: "+71"},
+			err:  amocrm.ErrUnknownCustomField,
+		},
+		{
+			name: "unexported field",
+			src:  &unexportedFieldLead{budget: 1},
+			err:  amocrm.ErrInvalidCustomFieldTag,
+		},
+		{
+			name: "enum composite value",
+			src:  &enumDateLead{PaidAt: time.Now()},
+			err:  amocrm.ErrUnsupportedCustomFieldKind,
+		},
+		{
+			name: "not a struct",
+			src:  1500,
+			err:  amocrm.ErrInvalidCustomFieldTarget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := tt.mapper.Encode(tt.src)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := json.Marshal(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(body) != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, body)
+			}
+		})
+	}
+}
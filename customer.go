@@ -0,0 +1,251 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+type (
+	CustomerRequestParams struct {
+		ID          []int                  `validate:"omitempty,gt=0,dive,required"`
+		LimitRows   int                    `validate:"required_with=LimitOffset,lte=500"`
+		LimitOffset int                    `validate:"omitempty"`
+		Filter      *CustomerRequestFilter `validate:"omitempty"`
+	}
+
+	CustomerRequestDateFilterType string
+
+	CustomerRequestFilter struct {
+		DateType     CustomerRequestDateFilterType `validate:"omitempty,oneof=create modify"`
+		DateFrom     Timestamp                     `validate:"omitempty"`
+		DateTo       Timestamp                     `validate:"omitempty"`
+		MainUser     []int                         `validate:"omitempty,gt=0,dive,required"`
+		NextDateFrom Timestamp                     `validate:"omitempty"`
+		NextDateTo   Timestamp                     `validate:"omitempty"`
+	}
+
+	CustomerAdd struct {
+		Name              string               `json:"name" validate:"required"`
+		NextDate          Timestamp            `json:"next_date" validate:"required"`
+		CreatedAt         Timestamp            `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp            `json:"updated_at,omitempty" validate:"omitempty"`
+		ResponsibleUserID int                  `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
+		CreatedBy         int                  `json:"created_by,string,omitempty" validate:"omitempty"`
+		NextPrice         int                  `json:"next_price,string,omitempty" validate:"omitempty"`
+		Periodicity       int                  `json:"periodicity,string,omitempty" validate:"omitempty"`
+		Tags              string               `json:"tags,omitempty" validate:"omitempty"`
+		ContactsID        []string             `json:"contacts_id,omitempty" validate:"omitempty,gt=0,dive,required"`
+		CompanyID         int                  `json:"company_id,string,omitempty" validate:"omitempty"`
+		CustomFields      []*UpdateCustomField `json:"custom_fields,omitempty" validate:"omitempty,gt=0,dive,required"`
+		RequestID         int                  `json:"request_id,string,omitempty" validate:"omitempty"`
+	}
+
+	CustomerUpdate struct {
+		ID                int                  `json:"id,string" validate:"required"`
+		Name              string               `json:"name,omitempty" validate:"omitempty"`
+		NextDate          Timestamp            `json:"next_date,omitempty" validate:"omitempty"`
+		CreatedAt         Timestamp            `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp            `json:"updated_at" validate:"required"`
+		ResponsibleUserID int                  `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
+		CreatedBy         int                  `json:"created_by,string,omitempty" validate:"omitempty"`
+		NextPrice         int                  `json:"next_price,string,omitempty" validate:"omitempty"`
+		Periodicity       int                  `json:"periodicity,string,omitempty" validate:"omitempty"`
+		Tags              string               `json:"tags,omitempty" validate:"omitempty"`
+		ContactsID        []string             `json:"contacts_id,omitempty" validate:"omitempty,gt=0,dive,required"`
+		CompanyID         int                  `json:"company_id,string,omitempty" validate:"omitempty"`
+		CustomFields      []*UpdateCustomField `json:"custom_fields,omitempty" validate:"omitempty,gt=0,dive,required"`
+		Unlink            *Unlink              `json:"unlink,omitempty" validate:"omitempty"`
+	}
+
+	AddCustomerRequest struct {
+		Add []*CustomerAdd `json:"add" validate:"required,dive,required"`
+	}
+
+	UpdateCustomerRequest struct {
+		Update []*CustomerUpdate `json:"update" validate:"required,dive,required"`
+	}
+
+	GetCustomerResponse struct {
+		Links    *Links `json:"_links" validate:"omitempty"`
+		Embedded struct {
+			Items []*Customer `json:"items" validate:"required,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+		Response *AmoError `json:"response,omitempty" validate:"omitempty"`
+	}
+
+	Customer struct {
+		ID                int       `json:"id" validate:"required"`
+		Name              string    `json:"name" validate:"required"`
+		ResponsibleUserID int       `json:"responsible_user_id" validate:"required"`
+		CreatedBy         int       `json:"created_by" validate:"required"`
+		CreatedAt         Timestamp `json:"created_at" validate:"required"`
+		UpdatedAt         Timestamp `json:"updated_at" validate:"required"`
+		AccountID         int       `json:"account_id" validate:"required"`
+		UpdatedBy         int       `json:"updated_by" validate:"omitempty"`
+		IsDeleted         bool      `json:"is_deleted" validate:"omitempty"`
+		StatusID          int       `json:"status_id,omitempty" validate:"omitempty"`
+		PeriodID          int       `json:"period_id,omitempty" validate:"omitempty"`
+		NextPrice         int       `json:"next_price,omitempty" validate:"omitempty"`
+		NextDate          Timestamp `json:"next_date,omitempty" validate:"omitempty"`
+		Periodicity       int       `json:"periodicity,omitempty" validate:"omitempty"`
+		ClosestTaskAt     Timestamp `json:"closest_task_at,omitempty" validate:"omitempty"`
+		Company           struct {
+			ID    int    `json:"id" validate:"omitempty"`
+			Name  string `json:"name" validate:"omitempty"`
+			Links *Links `json:"_links" validate:"omitempty"`
+		} `json:"company,omitempty" validate:"omitempty"`
+		Contacts struct {
+			ID    []int  `json:"id" validate:"omitempty,dive,required"`
+			Links *Links `json:"_links" validate:"omitempty"`
+		} `json:"contacts,omitempty" validate:"omitempty"`
+		Tags         []*Tag       `json:"tags,omitempty" validate:"omitempty,dive,required"`
+		CustomFields CustomFields `json:"custom_fields,omitempty" validate:"omitempty,dive,required"`
+		Links        *Links       `json:"_links" validate:"required"`
+	}
+)
+
+const (
+	CreateDateCustomerFilter CustomerRequestDateFilterType = "create"
+	ModifyDateCustomerFilter CustomerRequestDateFilterType = "modify"
+)
+
+var (
+	customerArrayFields = [][]byte{
+		[]byte("tags"),
+		[]byte("custom_fields"),
+	}
+)
+
+func (c *Client) AddCustomer(ctx context.Context, customer *CustomerAdd) (int, error) {
+	if err := c.validator.Struct(customer); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.doPost(ctx, c.baseURL+customersURI, newRetryableRequest(&AddCustomerRequest{Add: []*CustomerAdd{customer}}, customer.RequestID != 0))
+	if err != nil {
+		return 0, err
+	}
+
+	return c.getResponseID(resp)
+}
+
+func (c *Client) UpdateCustomer(ctx context.Context, customer *CustomerUpdate) (int, error) {
+	if err := c.validator.Struct(customer); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.doPost(ctx, c.baseURL+customersURI, &UpdateCustomerRequest{Update: []*CustomerUpdate{customer}})
+	if err != nil {
+		return 0, err
+	}
+
+	return c.getResponseID(resp)
+}
+
+func (c *Client) AddCustomers(ctx context.Context, customers []*CustomerAdd) ([]*BatchResult, error) {
+	if err := c.validator.Var(customers, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	requestIDs := make([]int, len(customers))
+	for i, customer := range customers {
+		requestIDs[i] = customer.RequestID
+	}
+
+	return c.postAddBatch(ctx, customersURI, requestIDs, func(from, to int) interface{} {
+		batch := make([]*CustomerAdd, 0, to-from)
+		for i, customer := range customers[from:to] {
+			item := *customer
+			item.RequestID = requestIDs[from+i]
+			batch = append(batch, &item)
+		}
+
+		return &AddCustomerRequest{Add: batch}
+	})
+}
+
+func (c *Client) UpdateCustomers(ctx context.Context, customers []*CustomerUpdate) ([]*BatchResult, error) {
+	if err := c.validator.Var(customers, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(customers))
+	for i, customer := range customers {
+		ids[i] = customer.ID
+	}
+
+	return c.postUpdateBatch(ctx, customersURI, ids, func(from, to int) interface{} {
+		return &UpdateCustomerRequest{Update: customers[from:to]}
+	})
+}
+
+func (c *Client) GetCustomers(ctx context.Context, reqParams *CustomerRequestParams) ([]*Customer, error) {
+	if err := c.validator.Struct(reqParams); err != nil {
+		return nil, err
+	}
+
+	addValues := make(map[string]string)
+	if reqParams.ID != nil {
+		addValues["id"] = joinIntSlice(reqParams.ID)
+	}
+	if reqParams.LimitRows != 0 {
+		addValues["limit_rows"] = strconv.Itoa(reqParams.LimitRows)
+		if reqParams.LimitOffset != 0 {
+			addValues["limit_offset"] = strconv.Itoa(reqParams.LimitOffset)
+		}
+	}
+	if reqParams.Filter != nil {
+		if reqParams.Filter.DateType != "" {
+			addValues["filter[date][type]"] = string(reqParams.Filter.DateType)
+			if reqParams.Filter.DateFrom != 0 {
+				addValues["filter[date][from]"] = reqParams.Filter.DateFrom.String()
+			}
+			if reqParams.Filter.DateTo != 0 {
+				addValues["filter[date][to]"] = reqParams.Filter.DateTo.String()
+			}
+		}
+		addIndexedValues(addValues, "filter[main_user]", reqParams.Filter.MainUser)
+		if reqParams.Filter.NextDateFrom != 0 {
+			addValues["filter[next_date][from]"] = reqParams.Filter.NextDateFrom.String()
+		}
+		if reqParams.Filter.NextDateTo != 0 {
+			addValues["filter[next_date][to]"] = reqParams.Filter.NextDateTo.String()
+		}
+	}
+
+	body, err := c.doGet(ctx, c.baseURL+customersURI, addValues)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	customerResponse := new(GetCustomerResponse)
+	err = json.Unmarshal(body, customerResponse)
+	if err != nil {
+		// fix bad json serialization, where nil array is serialized as nil object
+		body = fixBadArraySerialization(body, customerArrayFields)
+		err = json.Unmarshal(body, customerResponse)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if customerResponse.Response != nil {
+		return nil, customerResponse.Response
+	}
+
+	err = c.validator.Struct(customerResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(customerResponse.Embedded.Items) == 0 {
+		return nil, ErrEmptyResponseItems
+	}
+
+	return customerResponse.Embedded.Items, nil
+}
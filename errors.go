@@ -1,6 +1,11 @@
 package amocrm
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
 
 type (
 	Error string
@@ -9,6 +14,15 @@ type (
 		ErrorDetail string `json:"error" validate:"required"`
 		ErrorCode   int    `json:"error_code,string" validate:"required"`
 	}
+
+	HTTPError struct {
+		StatusCode int
+		Method     string
+		URL        string
+		Body       []byte
+		RetryAfter time.Duration
+		Response   *AmoError
+	}
 )
 
 func (e Error) Error() string {
@@ -18,6 +32,53 @@ func (e *AmoError) Error() string {
 	return fmt.Sprintf("%s: %s", amoErrorTypeMap[e.ErrorCode], e.ErrorDetail)
 }
 
+func (e *AmoError) Is(target error) bool {
+	category, ok := amoErrorCategoryMap[e.ErrorCode]
+	return ok && category == target
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("http status not ok: %d %s %s", e.StatusCode, e.Method, e.URL)
+	if e.Response != nil {
+		msg += ": " + e.Response.Error()
+	}
+
+	return msg
+}
+
+func (e *HTTPError) Unwrap() error {
+	if e.Response == nil {
+		return nil
+	}
+
+	return e.Response
+}
+
+func (e *HTTPError) Is(target error) bool {
+	category, ok := httpStatusCategoryMap[e.StatusCode]
+	return ok && category == target
+}
+
+func newHTTPError(resp *http.Response, body []byte) *HTTPError {
+	httpErr := &HTTPError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	if resp.Request != nil {
+		httpErr.Method = resp.Request.Method
+		httpErr.URL = resp.Request.URL.String()
+	}
+
+	errorResponse := new(ErrorResponse)
+	if err := json.Unmarshal(body, errorResponse); err == nil && errorResponse.Response != nil && errorResponse.Response.ErrorCode != 0 {
+		httpErr.Response = errorResponse.Response
+	}
+
+	return httpErr
+}
+
 const (
 	AccountNotFoundCode          = 101
 	BodyMustBeJSONCode           = 102
@@ -116,8 +177,46 @@ var (
 	ErrEmptyLogin         Error = "empty_login"
 	ErrEmptyAPIHash       Error = "empty_api_hash"
 	ErrEmptyPhoneNumber   Error = "empty_phone_number"
+	ErrEmptyEmail         Error = "empty_email"
+	ErrEmptyContactKey    Error = "empty_contact_key"
 	ErrInvalidEventType   Error = "invalid_event_type"
+	ErrInvalidEntityType  Error = "invalid_entity_type"
 	ErrEmptyResponseItems Error = "empty_response_items"
+	ErrEmptyEntityID      Error = "empty_entity_id"
+	ErrUnknownTimezone    Error = "unknown_timezone"
+
+	ErrInvalidCustomFieldTag      Error = "invalid_custom_field_tag"
+	ErrInvalidCustomFieldTarget   Error = "invalid_custom_field_target"
+	ErrUnknownCustomField         Error = "unknown_custom_field"
+	ErrUnsupportedCustomFieldKind Error = "unsupported_custom_field_kind"
+
+	ErrWebhookRejected       Error = "webhook_rejected"
+	ErrInvalidWebhookPayload Error = "invalid_webhook_payload"
+
+	ErrInvalidLeadStatus Error = "invalid_lead_status"
+
+	ErrUpdateConflict Error = "update_conflict"
+
+	ErrInvalidCallStatus   Error = "invalid_call_status"
+	ErrUnsupportedNoteKind Error = "unsupported_note_kind"
+
+	ErrBatchPartialFailure   Error = "batch_partial_failure"
+	ErrBatchItemNotProcessed Error = "batch_item_not_processed"
+	ErrDuplicateBatchItem    Error = "duplicate_batch_item"
+
+	ErrUnauthorized Error = "unauthorized"
+	ErrValidation   Error = "validation_failed"
+	ErrNotFound     Error = "not_found"
+	ErrQuota        Error = "quota_exceeded"
+	ErrRateLimited  Error = "rate_limited"
+
+	ErrOAuth2NotConfigured    Error = "oauth2_not_configured"
+	ErrEmptyClientID          Error = "empty_client_id"
+	ErrEmptyClientSecret      Error = "empty_client_secret"
+	ErrEmptyRedirectURI       Error = "empty_redirect_uri"
+	ErrEmptyAuthorizationCode Error = "empty_authorization_code"
+	ErrEmptyToken             Error = "empty_token"
+	ErrEmptyRefreshToken      Error = "empty_refresh_token"
 
 	amoErrorTypeMap = map[int]string{
 		AccountNotFoundCode:          AccountNotFound,
@@ -166,4 +265,56 @@ var (
 
 		NoContentCode: NoContent,
 	}
+
+	amoErrorCategoryMap = map[int]Error{
+		AccountNotFoundCode:          ErrNotFound,
+		BodyMustBeJSONCode:           ErrValidation,
+		InvalidRequestParametersCode: ErrValidation,
+		InvalidRequestMethodCode:     ErrValidation,
+
+		InvalidCredentialsCode:    ErrUnauthorized,
+		CaptchaInputRequiredCode:  ErrUnauthorized,
+		DisabledAccountCode:       ErrUnauthorized,
+		UnauthorizedIPAddressCode: ErrUnauthorized,
+
+		ContactAddEmptyArrayCode:               ErrValidation,
+		ContactAddInsufficientAccessRightsCode: ErrUnauthorized,
+		ContactAddCustomFieldNotFoundCode:      ErrValidation,
+
+		ContactsEmptyRequestCode:         ErrValidation,
+		ContactsInvalidRequestMethodCode: ErrValidation,
+
+		ContactUpdateEmptyArrayCode:                 ErrValidation,
+		ContactUpdatedRequiredParametersMissingCode: ErrValidation,
+		ContactUpdateCustomFieldNotFoundCode:        ErrValidation,
+
+		LeadAddEmptyArrayCode:                   ErrValidation,
+		LeadEmptyRequestCode:                    ErrValidation,
+		LeadInvalidRequestMethodCode:            ErrValidation,
+		LeadUpdateEmptyArrayCode:                ErrValidation,
+		LeadUpdateRequiredParametersMissingCode: ErrValidation,
+
+		LeadCustomFieldInvalidIDCode: ErrValidation,
+
+		TooManyLinkedEntitiesCode: ErrQuota,
+
+		InvalidRequestCode:          ErrValidation,
+		AccountNotFoundOnServerCode: ErrNotFound,
+		SubscriptionExpireCode:      ErrQuota,
+		AccountBlockedCode:          ErrUnauthorized,
+
+		RateLimitExceededCode: ErrRateLimited,
+
+		NoContentCode: ErrNotFound,
+	}
+
+	httpStatusCategoryMap = map[int]Error{
+		http.StatusBadRequest:          ErrValidation,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusPaymentRequired:     ErrQuota,
+		http.StatusForbidden:           ErrUnauthorized,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusUnprocessableEntity: ErrValidation,
+		http.StatusTooManyRequests:     ErrRateLimited,
+	}
 )
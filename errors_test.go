@@ -0,0 +1,68 @@
+package amocrm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+func TestErrorCategories(t *testing.T) {
+	categories := []error{amocrm.ErrUnauthorized, amocrm.ErrValidation, amocrm.ErrNotFound, amocrm.ErrQuota, amocrm.ErrRateLimited}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		errorCode  int
+		is         []error
+	}{
+		{name: "bad request", statusCode: http.StatusBadRequest, is: []error{amocrm.ErrValidation}},
+		{name: "payment required", statusCode: http.StatusPaymentRequired, is: []error{amocrm.ErrQuota}},
+		{name: "forbidden", statusCode: http.StatusForbidden, is: []error{amocrm.ErrUnauthorized}},
+		{name: "not found", statusCode: http.StatusNotFound, is: []error{amocrm.ErrNotFound}},
+		{name: "too many requests", statusCode: http.StatusTooManyRequests, is: []error{amocrm.ErrRateLimited}},
+		{name: "internal server error", statusCode: http.StatusInternalServerError},
+		{name: "account blocked", statusCode: http.StatusForbidden, errorCode: amocrm.AccountBlockedCode, is: []error{amocrm.ErrUnauthorized}},
+		{name: "too many linked entities", statusCode: http.StatusBadRequest, errorCode: amocrm.TooManyLinkedEntitiesCode, is: []error{amocrm.ErrValidation, amocrm.ErrQuota}},
+		{name: "subscription expired", statusCode: http.StatusInternalServerError, errorCode: amocrm.SubscriptionExpireCode, is: []error{amocrm.ErrQuota}},
+		{name: "uncategorized code", statusCode: http.StatusInternalServerError, errorCode: amocrm.ContactAddNotProcessedCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestClient(t, amocrm.WithRetryPolicy(nil))
+
+			failure := &amocrmtest.Failure{Method: http.MethodGet, Path: leadsPath, StatusCode: tt.statusCode}
+			if tt.errorCode != 0 {
+				failure.Body = amocrmtest.ResponseError(tt.errorCode, tt.name)
+			}
+			s.Fail(failure)
+
+			_, err := c.GetLeads(context.Background(), &amocrm.LeadRequestParams{})
+
+			var httpErr *amocrm.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.statusCode {
+				t.Fatalf("expected HTTPError with status %d, got %v", tt.statusCode, err)
+			}
+
+			var amoErr *amocrm.AmoError
+			if errors.As(err, &amoErr) != (tt.errorCode != 0) || amoErr != nil && amoErr.ErrorCode != tt.errorCode {
+				t.Fatalf("expected AmoError with code %d, got %v", tt.errorCode, err)
+			}
+
+			for _, category := range categories {
+				expected := false
+				for _, is := range tt.is {
+					expected = expected || is == category
+				}
+
+				if errors.Is(err, category) != expected {
+					t.Fatalf("expected errors.Is(%v) to be %t, got error %v", category, expected, err)
+				}
+			}
+		})
+	}
+}
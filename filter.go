@@ -0,0 +1,39 @@
+package amocrm
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type DateRangeFilter struct {
+	From time.Time `validate:"omitempty"`
+	To   time.Time `validate:"omitempty"`
+}
+
+func addDateRangeFilter(values map[string]string, key string, r *DateRangeFilter) {
+	if r == nil {
+		return
+	}
+
+	if !r.From.IsZero() {
+		values[key+"[from]"] = strconv.FormatInt(r.From.Unix(), 10)
+	}
+	if !r.To.IsZero() {
+		values[key+"[to]"] = strconv.FormatInt(r.To.Unix(), 10)
+	}
+}
+
+func addIndexedValues(values map[string]string, key string, ids []int) {
+	for i, id := range ids {
+		values[key+"["+strconv.Itoa(i)+"]"] = strconv.Itoa(id)
+	}
+}
+
+func ifModifiedSinceHeader(t time.Time) http.Header {
+	if t.IsZero() {
+		return nil
+	}
+
+	return http.Header{"If-Modified-Since": []string{t.UTC().Format(http.TimeFormat)}}
+}
@@ -0,0 +1,73 @@
+package amocrm_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+// newTestClient starts the fake server and returns the authorized client, which retries quickly and is not rate limited
+func newTestClient(t *testing.T, opts ...amocrm.ClientOption) (*amocrmtest.Server, *amocrm.Client) {
+	t.Helper()
+
+	s := amocrmtest.NewServer()
+	t.Cleanup(s.Close)
+
+	return s, newServerClient(t, s, opts...)
+}
+
+func newServerClient(t *testing.T, s *amocrmtest.Server, opts ...amocrm.ClientOption) *amocrm.Client {
+	t.Helper()
+
+	opts = append([]amocrm.ClientOption{
+		amocrm.WithRateLimit(0, 0),
+		amocrm.WithRetryPolicy(fastRetryPolicy()),
+	}, opts...)
+
+	c, err := s.Client(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Authorize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+// fastRetryPolicy retries within milliseconds instead of the default half a second
+func fastRetryPolicy() *amocrm.RetryPolicy {
+	return &amocrm.RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		MaxDelay:             10 * time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway},
+	}
+}
+
+func countRequests(s *amocrmtest.Server, method, path string) int {
+	n := 0
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+
+	return n
+}
+
+func addTestLead(t *testing.T, c *amocrm.Client, name string) int {
+	t.Helper()
+
+	id, err := c.AddLead(context.Background(), &amocrm.LeadAdd{Name: name, StatusID: 10, PipelineID: amocrmtest.DefaultPipelineID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
@@ -0,0 +1,341 @@
+package amocrm
+
+import (
+	"context"
+	"errors"
+)
+
+type (
+	IteratorOption func(it *iterator)
+
+	iterator struct {
+		fetch    func(ctx context.Context, limit, offset int) ([]interface{}, error)
+		limit    int
+		offset   int
+		items    []interface{}
+		pos      int
+		current  interface{}
+		err      error
+		done     bool
+		prefetch bool
+		pending  chan *iteratorPage
+	}
+
+	iteratorPage struct {
+		items []interface{}
+		err   error
+	}
+
+	LeadIterator struct {
+		iterator
+	}
+
+	ContactIterator struct {
+		iterator
+	}
+
+	CompanyIterator struct {
+		iterator
+	}
+
+	CustomerIterator struct {
+		iterator
+	}
+
+	TransactionIterator struct {
+		iterator
+	}
+
+	NoteIterator struct {
+		iterator
+	}
+
+	TaskIterator struct {
+		iterator
+	}
+)
+
+const (
+	// amoCRM API v2 returns no more than 500 items per page
+	maxPageSize = 500
+)
+
+func WithPrefetch() IteratorOption {
+	return func(it *iterator) {
+		it.prefetch = true
+	}
+}
+
+func newIterator(limit, offset int, fetch func(ctx context.Context, limit, offset int) ([]interface{}, error), opts []IteratorOption) iterator {
+	if limit <= 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	it := iterator{
+		fetch:  fetch,
+		limit:  limit,
+		offset: offset,
+	}
+
+	for _, o := range opts {
+		o(&it)
+	}
+
+	return it
+}
+
+func (it *iterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.pos < len(it.items) {
+		it.current = it.items[it.pos]
+		it.pos++
+		return true
+	}
+
+	it.current = nil
+	if it.done {
+		return false
+	}
+
+	items, err := it.nextPage(ctx)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	// short page means there is nothing left to fetch
+	if len(items) < it.limit {
+		it.done = true
+	}
+
+	if len(items) == 0 {
+		return false
+	}
+
+	it.items = items
+	it.current = items[0]
+	it.pos = 1
+
+	return true
+}
+
+func (it *iterator) Err() error {
+	return it.err
+}
+
+func (it *iterator) nextPage(ctx context.Context) ([]interface{}, error) {
+	var page *iteratorPage
+	if it.pending != nil {
+		select {
+		case page = <-it.pending:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		it.pending = nil
+	} else {
+		page = it.load(ctx, it.offset)
+	}
+
+	if page.err != nil {
+		return nil, page.err
+	}
+
+	it.offset += it.limit
+
+	if it.prefetch && len(page.items) == it.limit {
+		// channel is buffered, so the goroutine never blocks, even if the iterator is abandoned
+		it.pending = make(chan *iteratorPage, 1)
+		go func(offset int, pending chan<- *iteratorPage) {
+			pending <- it.load(ctx, offset)
+		}(it.offset, it.pending)
+	}
+
+	return page.items, nil
+}
+
+func (it *iterator) load(ctx context.Context, offset int) *iteratorPage {
+	items, err := it.fetch(ctx, it.limit, offset)
+	if errors.Is(err, ErrEmptyResponseItems) {
+		return &iteratorPage{}
+	}
+
+	return &iteratorPage{items: items, err: err}
+}
+
+func (c *Client) IterateLeads(reqParams *LeadRequestParams, opts ...IteratorOption) *LeadIterator {
+	params := LeadRequestParams{}
+	if reqParams != nil {
+		params = *reqParams
+	}
+
+	return &LeadIterator{newIterator(params.LimitRows, params.LimitOffset, func(ctx context.Context, limit, offset int) ([]interface{}, error) {
+		p := params
+		p.LimitRows, p.LimitOffset = limit, offset
+
+		leads, err := c.GetLeads(ctx, &p)
+		items := make([]interface{}, 0, len(leads))
+		for _, lead := range leads {
+			items = append(items, lead)
+		}
+
+		return items, err
+	}, opts)}
+}
+
+func (it *LeadIterator) Lead() *Lead {
+	lead, _ := it.current.(*Lead)
+	return lead
+}
+
+func (c *Client) IterateContacts(reqParams *ContactRequestParams, opts ...IteratorOption) *ContactIterator {
+	params := ContactRequestParams{}
+	if reqParams != nil {
+		params = *reqParams
+	}
+
+	return &ContactIterator{newIterator(params.LimitRows, params.LimitOffset, func(ctx context.Context, limit, offset int) ([]interface{}, error) {
+		p := params
+		p.LimitRows, p.LimitOffset = limit, offset
+
+		contacts, err := c.GetContacts(ctx, &p)
+		items := make([]interface{}, 0, len(contacts))
+		for _, contact := range contacts {
+			items = append(items, contact)
+		}
+
+		return items, err
+	}, opts)}
+}
+
+func (it *ContactIterator) Contact() *Contact {
+	contact, _ := it.current.(*Contact)
+	return contact
+}
+
+func (c *Client) IterateCompanies(reqParams *CompanyRequestParams, opts ...IteratorOption) *CompanyIterator {
+	params := CompanyRequestParams{}
+	if reqParams != nil {
+		params = *reqParams
+	}
+
+	return &CompanyIterator{newIterator(params.LimitRows, params.LimitOffset, func(ctx context.Context, limit, offset int) ([]interface{}, error) {
+		p := params
+		p.LimitRows, p.LimitOffset = limit, offset
+
+		companies, err := c.GetCompanies(ctx, &p)
+		items := make([]interface{}, 0, len(companies))
+		for _, company := range companies {
+			items = append(items, company)
+		}
+
+		return items, err
+	}, opts)}
+}
+
+func (it *CompanyIterator) Company() *Company {
+	company, _ := it.current.(*Company)
+	return company
+}
+
+func (c *Client) IterateCustomers(reqParams *CustomerRequestParams, opts ...IteratorOption) *CustomerIterator {
+	params := CustomerRequestParams{}
+	if reqParams != nil {
+		params = *reqParams
+	}
+
+	return &CustomerIterator{newIterator(params.LimitRows, params.LimitOffset, func(ctx context.Context, limit, offset int) ([]interface{}, error) {
+		p := params
+		p.LimitRows, p.LimitOffset = limit, offset
+
+		customers, err := c.GetCustomers(ctx, &p)
+		items := make([]interface{}, 0, len(customers))
+		for _, customer := range customers {
+			items = append(items, customer)
+		}
+
+		return items, err
+	}, opts)}
+}
+
+func (it *CustomerIterator) Customer() *Customer {
+	customer, _ := it.current.(*Customer)
+	return customer
+}
+
+func (c *Client) IterateTransactions(reqParams *TransactionRequestParams, opts ...IteratorOption) *TransactionIterator {
+	params := TransactionRequestParams{}
+	if reqParams != nil {
+		params = *reqParams
+	}
+
+	return &TransactionIterator{newIterator(params.LimitRows, params.LimitOffset, func(ctx context.Context, limit, offset int) ([]interface{}, error) {
+		p := params
+		p.LimitRows, p.LimitOffset = limit, offset
+
+		transactions, err := c.GetTransactions(ctx, &p)
+		items := make([]interface{}, 0, len(transactions))
+		for _, transaction := range transactions {
+			items = append(items, transaction)
+		}
+
+		return items, err
+	}, opts)}
+}
+
+func (it *TransactionIterator) Transaction() *Transaction {
+	transaction, _ := it.current.(*Transaction)
+	return transaction
+}
+
+func (c *Client) IterateNotes(reqParams *NoteRequestParams, opts ...IteratorOption) *NoteIterator {
+	params := NoteRequestParams{}
+	if reqParams != nil {
+		params = *reqParams
+	}
+
+	return &NoteIterator{newIterator(params.LimitRows, params.LimitOffset, func(ctx context.Context, limit, offset int) ([]interface{}, error) {
+		p := params
+		p.LimitRows, p.LimitOffset = limit, offset
+
+		notes, err := c.GetNotes(ctx, &p)
+		items := make([]interface{}, 0, len(notes))
+		for _, note := range notes {
+			items = append(items, note)
+		}
+
+		return items, err
+	}, opts)}
+}
+
+func (it *NoteIterator) Note() *Note {
+	note, _ := it.current.(*Note)
+	return note
+}
+
+func (c *Client) IterateTasks(reqParams *TaskRequestParams, opts ...IteratorOption) *TaskIterator {
+	params := TaskRequestParams{}
+	if reqParams != nil {
+		params = *reqParams
+	}
+
+	return &TaskIterator{newIterator(params.LimitRows, params.LimitOffset, func(ctx context.Context, limit, offset int) ([]interface{}, error) {
+		p := params
+		p.LimitRows, p.LimitOffset = limit, offset
+
+		tasks, err := c.GetTasks(ctx, &p)
+		items := make([]interface{}, 0, len(tasks))
+		for _, task := range tasks {
+			items = append(items, task)
+		}
+
+		return items, err
+	}, opts)}
+}
+
+func (it *TaskIterator) Task() *Task {
+	task, _ := it.current.(*Task)
+	return task
+}
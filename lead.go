@@ -3,7 +3,9 @@ package amocrm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strconv"
+	"time"
 )
 
 type (
@@ -15,6 +17,7 @@ type (
 		Query             string             `validate:"omitempty"`
 		Status            []int              `validate:"omitempty,gt=0,dive,required"`
 		Filter            *LeadRequestFilter `validate:"omitempty"`
+		IfModifiedSince   time.Time          `validate:"omitempty"`
 	}
 
 	LeadRequestTasksFilter int
@@ -22,14 +25,18 @@ type (
 	LeadRequestActiveFilter int
 
 	LeadRequestFilter struct {
-		Tasks  LeadRequestTasksFilter  `validate:"omitempty,oneof=1 2"`
-		Active LeadRequestActiveFilter `validate:"omitempty,eq=1"`
+		Tasks      LeadRequestTasksFilter  `validate:"omitempty,oneof=1 2"`
+		Active     LeadRequestActiveFilter `validate:"omitempty,eq=1"`
+		CreatedAt  *DateRangeFilter        `validate:"omitempty"`
+		UpdatedAt  *DateRangeFilter        `validate:"omitempty"`
+		ClosedAt   *DateRangeFilter        `validate:"omitempty"`
+		PipelineID []int                   `validate:"omitempty,gt=0,dive,required"`
 	}
 
 	LeadAdd struct {
 		Name              string               `json:"name" validate:"required"`
-		CreatedAt         int                  `json:"created_at,string,omitempty" validate:"omitempty"`
-		UpdatedAt         int                  `json:"updated_at,string,omitempty" validate:"omitempty"`
+		CreatedAt         Timestamp            `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp            `json:"updated_at,omitempty" validate:"omitempty"`
 		StatusID          int                  `json:"status_id,string" validate:"required"`
 		PipelineID        int                  `json:"pipeline_id,string,omitempty" validate:"omitempty"`
 		ResponsibleUserID int                  `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
@@ -44,8 +51,8 @@ type (
 	LeadUpdate struct {
 		ID                int                  `json:"id,string" validate:"required"`
 		Name              string               `json:"name,omitempty" validate:"omitempty"`
-		CreatedAt         int                  `json:"created_at,string,omitempty" validate:"omitempty"`
-		UpdatedAt         int                  `json:"updated_at,string" validate:"required"`
+		CreatedAt         Timestamp            `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp            `json:"updated_at" validate:"required"`
 		StatusID          int                  `json:"status_id,string,omitempty" validate:"omitempty"`
 		PipelineID        int                  `json:"pipeline_id,string,omitempty" validate:"omitempty"`
 		ResponsibleUserID int                  `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
@@ -75,24 +82,29 @@ type (
 	}
 
 	Lead struct {
-		ID                int    `json:"id" validate:"required"`
-		Name              string `json:"name" validate:"required"`
-		ResponsibleUserID int    `json:"responsible_user_id" validate:"required"`
-		CreatedBy         int    `json:"created_by" validate:"required"`
-		CreatedAt         int    `json:"created_at" validate:"required"`
-		UpdatedAt         int    `json:"updated_at" validate:"required"`
-		AccountID         int    `json:"account_id" validate:"required"`
-		IsDeleted         bool   `json:"is_deleted" validate:"omitempty"`
+		ID                int       `json:"id" validate:"required"`
+		Name              string    `json:"name" validate:"required"`
+		ResponsibleUserID int       `json:"responsible_user_id" validate:"required"`
+		CreatedBy         int       `json:"created_by" validate:"required"`
+		CreatedAt         Timestamp `json:"created_at" validate:"required"`
+		UpdatedAt         Timestamp `json:"updated_at" validate:"required"`
+		AccountID         int       `json:"account_id" validate:"required"`
+		IsDeleted         bool      `json:"is_deleted" validate:"omitempty"`
 		MainContact       struct {
 			ID    int    `json:"id" validate:"omitempty"`
 			Links *Links `json:"_links" validate:"omitempty"`
 		} `json:"main_contact,omitempty" validate:"omitempty"`
-		GroupID       int            `json:"group_id,omitempty" validate:"omitempty"`
-		ClosedAt      int            `json:"closed_at,omitempty" validate:"omitempty"`
-		ClosestTaskAt int            `json:"closest_task_at,omitempty" validate:"omitempty"`
-		Tags          []*Tag         `json:"tags,omitempty" validate:"omitempty,dive,required"`
-		CustomFields  []*CustomField `json:"custom_fields,omitempty" validate:"omitempty"`
-		Contact       struct {
+		GroupID       int          `json:"group_id,omitempty" validate:"omitempty"`
+		ClosedAt      Timestamp    `json:"closed_at,omitempty" validate:"omitempty"`
+		ClosestTaskAt Timestamp    `json:"closest_task_at,omitempty" validate:"omitempty"`
+		Tags          []*Tag       `json:"tags,omitempty" validate:"omitempty,dive,required"`
+		CustomFields  CustomFields `json:"custom_fields,omitempty" validate:"omitempty"`
+		Company       struct {
+			ID    int    `json:"id" validate:"omitempty"`
+			Name  string `json:"name" validate:"omitempty"`
+			Links *Links `json:"_links" validate:"omitempty"`
+		} `json:"company,omitempty" validate:"omitempty"`
+		Contact struct {
 			ID    []int  `json:"id" validate:"omitempty,dive,required"`
 			Links *Links `json:"_links" validate:"omitempty"`
 		} `json:"contacts,omitempty" validate:"omitempty"`
@@ -125,7 +137,7 @@ func (c *Client) AddLead(ctx context.Context, lead *LeadAdd) (int, error) {
 		return 0, err
 	}
 
-	resp, err := c.doPost(ctx, c.baseURL+leadsURI, &AddLeadRequest{Add: []*LeadAdd{lead}})
+	resp, err := c.doPost(ctx, c.baseURL+leadsURI, newRetryableRequest(&AddLeadRequest{Add: []*LeadAdd{lead}}, lead.RequestID != 0))
 	if err != nil {
 		return 0, err
 	}
@@ -146,6 +158,43 @@ func (c *Client) UpdateLead(ctx context.Context, lead *LeadUpdate) (int, error)
 	return c.getResponseID(resp)
 }
 
+func (c *Client) AddLeads(ctx context.Context, leads []*LeadAdd) ([]*BatchResult, error) {
+	if err := c.validator.Var(leads, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	requestIDs := make([]int, len(leads))
+	for i, lead := range leads {
+		requestIDs[i] = lead.RequestID
+	}
+
+	return c.postAddBatch(ctx, leadsURI, requestIDs, func(from, to int) interface{} {
+		batch := make([]*LeadAdd, 0, to-from)
+		for i, lead := range leads[from:to] {
+			item := *lead
+			item.RequestID = requestIDs[from+i]
+			batch = append(batch, &item)
+		}
+
+		return &AddLeadRequest{Add: batch}
+	})
+}
+
+func (c *Client) UpdateLeads(ctx context.Context, leads []*LeadUpdate) ([]*BatchResult, error) {
+	if err := c.validator.Var(leads, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(leads))
+	for i, lead := range leads {
+		ids[i] = lead.ID
+	}
+
+	return c.postUpdateBatch(ctx, leadsURI, ids, func(from, to int) interface{} {
+		return &UpdateLeadRequest{Update: leads[from:to]}
+	})
+}
+
 func (c *Client) GetLeads(ctx context.Context, reqParams *LeadRequestParams) ([]*Lead, error) {
 	if err := c.validator.Struct(reqParams); err != nil {
 		return nil, err
@@ -170,8 +219,20 @@ func (c *Client) GetLeads(ctx context.Context, reqParams *LeadRequestParams) ([]
 	if reqParams.Status != nil {
 		addValues["status"] = joinIntSlice(reqParams.Status)
 	}
+	if reqParams.Filter != nil {
+		if reqParams.Filter.Tasks != 0 {
+			addValues["filter[tasks]"] = strconv.Itoa(int(reqParams.Filter.Tasks))
+		}
+		if reqParams.Filter.Active != 0 {
+			addValues["filter[active]"] = strconv.Itoa(int(reqParams.Filter.Active))
+		}
+		addDateRangeFilter(addValues, "filter[date_create]", reqParams.Filter.CreatedAt)
+		addDateRangeFilter(addValues, "filter[date_modify]", reqParams.Filter.UpdatedAt)
+		addDateRangeFilter(addValues, "filter[date_close]", reqParams.Filter.ClosedAt)
+		addIndexedValues(addValues, "filter[pipe]", reqParams.Filter.PipelineID)
+	}
 
-	body, err := c.doGet(ctx, c.baseURL+leadsURI, addValues)
+	body, err := c.doGetWithHeader(ctx, c.baseURL+leadsURI, addValues, ifModifiedSinceHeader(reqParams.IfModifiedSince))
 	if err != nil {
 		return nil, err
 	}
@@ -206,3 +267,25 @@ func (c *Client) GetLeads(ctx context.Context, reqParams *LeadRequestParams) ([]
 
 	return leadResponse.Embedded.Items, nil
 }
+
+func (c *Client) getLead(ctx context.Context, leadID int) (*Lead, error) {
+	if leadID == 0 {
+		return nil, ErrEmptyEntityID
+	}
+
+	leads, err := c.GetLeads(ctx, &LeadRequestParams{ID: []int{leadID}})
+	if errors.Is(err, ErrEmptyResponseItems) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lead := range leads {
+		if lead.ID == leadID {
+			return lead, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
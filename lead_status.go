@@ -0,0 +1,123 @@
+package amocrm
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	// won and lost statuses are shared by all pipelines
+	WonLeadStatusID  = 142
+	LostLeadStatusID = 143
+)
+
+func (c *Client) MoveLead(ctx context.Context, leadID, pipelineID, statusID int) error {
+	if leadID == 0 {
+		return ErrEmptyEntityID
+	}
+
+	if err := c.checkLeadStatus(ctx, pipelineID, statusID); err != nil {
+		return err
+	}
+
+	lead, err := c.getLead(ctx, leadID)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.UpdateLead(ctx, &LeadUpdate{
+		ID:         lead.ID,
+		UpdatedAt:  nextUpdatedAt(lead.UpdatedAt),
+		PipelineID: pipelineID,
+		StatusID:   statusID,
+	})
+
+	return err
+}
+
+func (c *Client) WinLead(ctx context.Context, leadID int) error {
+	lead, err := c.getLead(ctx, leadID)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.UpdateLead(ctx, &LeadUpdate{
+		ID:         lead.ID,
+		UpdatedAt:  nextUpdatedAt(lead.UpdatedAt),
+		PipelineID: lead.Pipeline.ID,
+		StatusID:   WonLeadStatusID,
+	})
+
+	return err
+}
+
+// LoseLead closes the lead as lost, loss reasons are supported only by API v4, so zero lossReasonID skips it
+func (c *Client) LoseLead(ctx context.Context, leadID int, lossReasonID int) error {
+	lead, err := c.getLead(ctx, leadID)
+	if err != nil {
+		return err
+	}
+
+	if lossReasonID == 0 {
+		_, err = c.UpdateLead(ctx, &LeadUpdate{
+			ID:         lead.ID,
+			UpdatedAt:  nextUpdatedAt(lead.UpdatedAt),
+			PipelineID: lead.Pipeline.ID,
+			StatusID:   LostLeadStatusID,
+		})
+
+		return err
+	}
+
+	_, err = c.V4().Leads().Update(ctx, []*V4Lead{{
+		ID:           lead.ID,
+		PipelineID:   lead.Pipeline.ID,
+		StatusID:     LostLeadStatusID,
+		LossReasonID: lossReasonID,
+	}})
+
+	return err
+}
+
+func (c *Client) checkLeadStatus(ctx context.Context, pipelineID, statusID int) error {
+	if pipelineID == 0 || statusID == 0 {
+		return ErrInvalidLeadStatus
+	}
+
+	pipelines, err := c.GetPipelines(ctx, &PipelineRequestParams{ID: pipelineID})
+	if errors.Is(err, ErrEmptyResponseItems) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	pipeline, ok := pipelines[strconv.Itoa(pipelineID)]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if statusID == WonLeadStatusID || statusID == LostLeadStatusID {
+		return nil
+	}
+
+	for _, status := range pipeline.Statuses {
+		if status.ID == statusID {
+			return nil
+		}
+	}
+
+	return ErrInvalidLeadStatus
+}
+
+// nextUpdatedAt returns the current time, but never older than the stored updated_at, which amoCRM rejects
+func nextUpdatedAt(stored Timestamp) Timestamp {
+	now := NewTimestamp(time.Now())
+	if now <= stored {
+		return stored + 1
+	}
+
+	return now
+}
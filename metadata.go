@@ -0,0 +1,261 @@
+package amocrm
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	EntityType string
+
+	// AccountMetadata caches account users, pipelines, custom fields, task and note types to resolve names into ids.
+	// Metadata is loaded lazily on the first lookup and reloaded when it gets older than ttl, zero ttl disables reloading.
+	AccountMetadata struct {
+		client    *Client
+		ttl       time.Duration
+		account   *AccountResponse
+		pipelines map[string]*Pipeline
+		loadedAt  time.Time
+		mu        sync.RWMutex
+		refreshMu sync.Mutex
+	}
+)
+
+const (
+	LeadEntityType     EntityType = "leads"
+	ContactEntityType  EntityType = "contacts"
+	CompanyEntityType  EntityType = "companies"
+	CustomerEntityType EntityType = "customers"
+)
+
+var (
+	metadataAccountWith = []AccountWithType{
+		AccountWithCustomFields,
+		AccountWithUsers,
+		AccountWithPipelines,
+		AccountWithGroups,
+		AccountWithNoteTypes,
+		AccountWithTaskTypes,
+	}
+)
+
+func (c *Client) NewAccountMetadata(ttl time.Duration) *AccountMetadata {
+	return &AccountMetadata{
+		client: c,
+		ttl:    ttl,
+	}
+}
+
+func (m *AccountMetadata) Refresh(ctx context.Context) error {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	return m.refresh(ctx)
+}
+
+// refresh must be called with m.refreshMu locked
+func (m *AccountMetadata) refresh(ctx context.Context) error {
+	account, err := m.client.GetAccount(ctx, &AccountRequestParams{With: metadataAccountWith})
+	if err != nil {
+		return err
+	}
+
+	if account == nil {
+		return ErrEmptyResponseItems
+	}
+
+	pipelines, err := m.client.GetPipelines(ctx, &PipelineRequestParams{})
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.account = account
+	m.pipelines = pipelines
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *AccountMetadata) Account(ctx context.Context) (*AccountResponse, error) {
+	if err := m.load(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.account, nil
+}
+
+func (m *AccountMetadata) PipelineByName(ctx context.Context, name string) (*Pipeline, error) {
+	if err := m.load(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.pipelineByName(name)
+}
+
+func (m *AccountMetadata) StatusByName(ctx context.Context, pipeline, name string) (*PipelineStatus, error) {
+	if err := m.load(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, err := m.pipelineByName(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, status := range p.Statuses {
+		if strings.EqualFold(status.Name, name) {
+			return status, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *AccountMetadata) UserByLogin(ctx context.Context, login string) (*User, error) {
+	if err := m.load(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, user := range m.account.Embedded.Users {
+		if strings.EqualFold(user.Login, login) {
+			return user, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *AccountMetadata) FieldByCode(ctx context.Context, entity EntityType, code string) (*CustomFieldInfo, error) {
+	fields, err := m.CustomFields(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		if strings.EqualFold(field.Code, code) {
+			return field, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *AccountMetadata) CustomFields(ctx context.Context, entity EntityType) (map[string]*CustomFieldInfo, error) {
+	if err := m.load(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	customFields := m.account.Embedded.CustomFields
+	switch entity {
+	case LeadEntityType:
+		return customFields.Leads, nil
+	case ContactEntityType:
+		return customFields.Contacts, nil
+	case CompanyEntityType:
+		return customFields.Companies, nil
+	case CustomerEntityType:
+		return customFields.Customers, nil
+	default:
+		return nil, ErrInvalidEntityType
+	}
+}
+
+func (m *AccountMetadata) CustomFieldMapper(ctx context.Context, entity EntityType) (*CustomFieldMapper, error) {
+	fields, err := m.CustomFields(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCustomFieldMapper(fields), nil
+}
+
+func (m *AccountMetadata) TaskTypeByName(ctx context.Context, name string) (*TaskType, error) {
+	if err := m.load(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, taskType := range m.account.Embedded.TaskTypes {
+		if strings.EqualFold(taskType.Name, name) {
+			return taskType, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *AccountMetadata) NoteTypeByCode(ctx context.Context, code string) (*NoteType, error) {
+	if err := m.load(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, noteType := range m.account.Embedded.NoteTypes {
+		if strings.EqualFold(noteType.Code, code) {
+			return noteType, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *AccountMetadata) load(ctx context.Context) error {
+	if m.fresh() {
+		return nil
+	}
+
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	// metadata has already been reloaded by a concurrent caller
+	if m.fresh() {
+		return nil
+	}
+
+	return m.refresh(ctx)
+}
+
+func (m *AccountMetadata) fresh() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.account == nil {
+		return false
+	}
+
+	return m.ttl <= 0 || time.Since(m.loadedAt) < m.ttl
+}
+
+// pipelineByName must be called with m.mu read locked
+func (m *AccountMetadata) pipelineByName(name string) (*Pipeline, error) {
+	for _, p := range m.pipelines {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
@@ -0,0 +1,185 @@
+package amocrm
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+type (
+	ModifyOption func(o *modifyOptions)
+
+	modifyOptions struct {
+		attempts int
+	}
+
+	LeadMutation    func(lead *Lead, update *LeadUpdate) error
+	ContactMutation func(contact *Contact, update *ContactUpdate) error
+	TaskMutation    func(task *Task, update *TaskUpdate) error
+
+	modifySend func() ([]*BatchResult, error)
+)
+
+const (
+	defaultModifyAttempts = 3
+
+	// amoCRM rejects an update item with this message, when updated_at is older than the stored one
+	updateConflictMessage = "older than in database"
+)
+
+func WithModifyAttempts(n int) ModifyOption {
+	return func(o *modifyOptions) {
+		if n > 0 {
+			o.attempts = n
+		}
+	}
+}
+
+// ModifyLead reads the lead, lets mutate fill the update and sends it with updated_at following the observed one.
+// Right before sending, updated_at is read again and the attempt starts over on a fresh copy, if it has moved.
+// A concurrent update landing between this check and the update itself is still overwritten, since amoCRM rejects
+// only updates older than the stored one, as well as an update made within the same second as the read,
+// since updated_at has a precision of seconds.
+func (c *Client) ModifyLead(ctx context.Context, id int, mutate LeadMutation, opts ...ModifyOption) error {
+	stored := func() (Timestamp, error) {
+		lead, err := c.getLead(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+
+		return lead.UpdatedAt, nil
+	}
+
+	return modify(opts, stored, func() (Timestamp, modifySend, error) {
+		lead, err := c.getLead(ctx, id)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		update := &LeadUpdate{ID: lead.ID}
+		if err := mutate(lead, update); err != nil {
+			return 0, nil, err
+		}
+		update.UpdatedAt = nextUpdatedAt(lead.UpdatedAt)
+
+		return lead.UpdatedAt, func() ([]*BatchResult, error) {
+			return c.UpdateLeads(ctx, []*LeadUpdate{update})
+		}, nil
+	})
+}
+
+// ModifyContact is ModifyLead for contacts, with the same guarantees
+func (c *Client) ModifyContact(ctx context.Context, id int, mutate ContactMutation, opts ...ModifyOption) error {
+	stored := func() (Timestamp, error) {
+		contact, err := c.getContact(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+
+		return contact.UpdatedAt, nil
+	}
+
+	return modify(opts, stored, func() (Timestamp, modifySend, error) {
+		contact, err := c.getContact(ctx, id)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		update := &ContactUpdate{ID: contact.ID}
+		if err := mutate(contact, update); err != nil {
+			return 0, nil, err
+		}
+		update.UpdatedAt = nextUpdatedAt(contact.UpdatedAt)
+
+		return contact.UpdatedAt, func() ([]*BatchResult, error) {
+			return c.UpdateContacts(ctx, []*ContactUpdate{update})
+		}, nil
+	})
+}
+
+// ModifyTask is ModifyLead for tasks, with the same guarantees
+func (c *Client) ModifyTask(ctx context.Context, id int, mutate TaskMutation, opts ...ModifyOption) error {
+	stored := func() (Timestamp, error) {
+		task, err := c.getTask(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+
+		return task.UpdatedAt, nil
+	}
+
+	return modify(opts, stored, func() (Timestamp, modifySend, error) {
+		task, err := c.getTask(ctx, id)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		// text is always sent, so it must be preserved unless the mutation changes it
+		update := &TaskUpdate{ID: task.ID, Text: task.Text}
+		if err := mutate(task, update); err != nil {
+			return 0, nil, err
+		}
+		update.UpdatedAt = nextUpdatedAt(task.UpdatedAt)
+
+		return task.UpdatedAt, func() ([]*BatchResult, error) {
+			return c.UpdateTasks(ctx, []*TaskUpdate{update})
+		}, nil
+	})
+}
+
+// modify runs attempts until the update is accepted, fails for a reason other than a conflict or attempts are exhausted.
+// The attempt is a conflict, when the stored updated_at differs from the observed one before or after sending,
+// or amoCRM reports it as such.
+func modify(opts []ModifyOption, stored func() (Timestamp, error), attempt func() (Timestamp, modifySend, error)) error {
+	o := modifyOptions{attempts: defaultModifyAttempts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for i := 0; i < o.attempts; i++ {
+		observed, send, err := attempt()
+		if err != nil {
+			return err
+		}
+
+		current, err := stored()
+		if err != nil {
+			return err
+		}
+
+		if current != observed {
+			continue
+		}
+
+		results, err := send()
+		if err != nil && !errors.Is(err, ErrBatchPartialFailure) {
+			return err
+		}
+
+		if len(results) == 0 {
+			return ErrEmptyResponseItems
+		}
+
+		updateErr := results[0].Err
+		if updateErr == nil {
+			return nil
+		}
+
+		if !IsUpdateConflict(updateErr) {
+			current, err := stored()
+			if err != nil {
+				return err
+			}
+
+			if current == observed {
+				return updateErr
+			}
+		}
+	}
+
+	return ErrUpdateConflict
+}
+
+func IsUpdateConflict(err error) bool {
+	return err != nil && (errors.Is(err, ErrUpdateConflict) || strings.Contains(err.Error(), updateConflictMessage))
+}
@@ -0,0 +1,151 @@
+package amocrm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+func TestModifyLeadConcurrentUpdate(t *testing.T) {
+	s, c := newTestClient(t)
+	ctx := context.Background()
+
+	id, err := c.AddLead(ctx, &amocrm.LeadAdd{
+		Name:       "lead",
+		StatusID:   10,
+		PipelineID: amocrmtest.DefaultPipelineID,
+		UpdatedAt:  amocrm.NewTimestamp(time.Now().Add(-time.Minute)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the rival writer runs on the same clock and stamps the lead with the current time
+	calls := 0
+	err = c.ModifyLead(ctx, id, func(lead *amocrm.Lead, update *amocrm.LeadUpdate) error {
+		calls++
+		if calls == 1 {
+			if _, err := c.UpdateLead(ctx, &amocrm.LeadUpdate{ID: id, Name: "other", UpdatedAt: amocrm.NewTimestamp(time.Now())}); err != nil {
+				return err
+			}
+		}
+
+		update.Name = lead.Name + "!"
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 2 || s.Lead(id).Name != "other!" {
+		t.Fatalf("expected the update on a fresh copy, got %q after %d calls", s.Lead(id).Name, calls)
+	}
+}
+
+func TestModifyLeadAttemptsExhausted(t *testing.T) {
+	s, c := newTestClient(t)
+	ctx := context.Background()
+	id := addTestLead(t, c, "lead")
+
+	err := c.ModifyLead(ctx, id, func(lead *amocrm.Lead, update *amocrm.LeadUpdate) error {
+		if _, err := c.UpdateLead(ctx, &amocrm.LeadUpdate{ID: id, Name: "other", UpdatedAt: lead.UpdatedAt + 1}); err != nil {
+			return err
+		}
+
+		update.Name = "mine"
+		return nil
+	}, amocrm.WithModifyAttempts(2))
+	if !errors.Is(err, amocrm.ErrUpdateConflict) {
+		t.Fatalf("expected ErrUpdateConflict, got %v", err)
+	}
+
+	if s.Lead(id).Name != "other" {
+		t.Fatalf("the concurrent update is overwritten with %q", s.Lead(id).Name)
+	}
+}
+
+func TestModifyErrors(t *testing.T) {
+	_, c := newTestClient(t)
+	ctx := context.Background()
+	id := addTestLead(t, c, "lead")
+	errMutate := errors.New("mutate")
+
+	for _, tt := range []struct {
+		name string
+		err  error
+		run  func() error
+	}{
+		{
+			name: "mutation error",
+			err:  errMutate,
+			run: func() error {
+				return c.ModifyLead(ctx, id, func(*amocrm.Lead, *amocrm.LeadUpdate) error { return errMutate })
+			},
+		},
+		{
+			name: "missing lead",
+			err:  amocrm.ErrNotFound,
+			run: func() error {
+				return c.ModifyLead(ctx, id+1000, func(*amocrm.Lead, *amocrm.LeadUpdate) error { return nil })
+			},
+		},
+		{
+			name: "missing contact",
+			err:  amocrm.ErrNotFound,
+			run: func() error {
+				return c.ModifyContact(ctx, id+1000, func(*amocrm.Contact, *amocrm.ContactUpdate) error { return nil })
+			},
+		},
+		{
+			name: "missing task",
+			err:  amocrm.ErrNotFound,
+			run: func() error {
+				return c.ModifyTask(ctx, id+1000, func(*amocrm.Task, *amocrm.TaskUpdate) error { return nil })
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestModifyContactAndTask(t *testing.T) {
+	s, c := newTestClient(t)
+	ctx := context.Background()
+	leadID := addTestLead(t, c, "lead")
+
+	contactID, err := c.AddContact(ctx, &amocrm.ContactAdd{Name: "contact"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ModifyContact(ctx, contactID, func(contact *amocrm.Contact, update *amocrm.ContactUpdate) error {
+		update.Name = contact.Name + "!"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	taskID, err := c.AddTask(ctx, &amocrm.TaskAdd{ElementID: leadID, ElementType: amocrm.LeadTaskElementType, TaskType: 1, Text: "call", CompleteTill: amocrm.NewTimestamp(time.Now().Add(time.Hour))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ModifyTask(ctx, taskID, func(task *amocrm.Task, update *amocrm.TaskUpdate) error {
+		update.IsCompleted = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Contact(contactID).Name != "contact!" || !s.Task(taskID).IsCompleted || s.Task(taskID).Text != "call" {
+		t.Fatalf("unexpected contact %+v or task %+v", s.Contact(contactID), s.Task(taskID))
+	}
+}
@@ -4,48 +4,72 @@ import (
 	"context"
 	"encoding/json"
 	"strconv"
+	"time"
 )
 
 type (
 	NoteRequestType string
 
 	NoteRequestParams struct {
-		Type        NoteRequestType `validate:"required,oneof=lead contact company task"`
-		ID          []int           `validate:"omitempty,gt=0,dive,required"`
-		LimitRows   int             `validate:"required_with=LimitOffset,lte=500"`
-		LimitOffset int             `validate:"omitempty"`
-		ElementID   []int           `validate:"omitempty,gt=0,dive,required"`
-		NoteType    []int           `validate:"omitempty,gt=0,dive,required"`
+		Type            NoteRequestType    `validate:"required,oneof=lead contact company task"`
+		ID              []int              `validate:"omitempty,gt=0,dive,required"`
+		LimitRows       int                `validate:"required_with=LimitOffset,lte=500"`
+		LimitOffset     int                `validate:"omitempty"`
+		ElementID       []int              `validate:"omitempty,gt=0,dive,required"`
+		NoteType        []int              `validate:"omitempty,gt=0,dive,required"`
+		Filter          *NoteRequestFilter `validate:"omitempty"`
+		IfModifiedSince time.Time          `validate:"omitempty"`
+	}
+
+	NoteRequestFilter struct {
+		CreatedAt *DateRangeFilter `validate:"omitempty"`
+		UpdatedAt *DateRangeFilter `validate:"omitempty"`
 	}
 
 	NotePostParameters struct {
-		UNIQ       string `json:"UNIQ" validate:"required"`
-		LINK       string `json:"LINK" validate:"required"`
-		PHONE      string `json:"PHONE" validate:"required"`
-		DURATION   int    `json:"DURATION" validate:"required"`
-		SRC        string `json:"SRC" validate:"required"`
-		FROM       string `json:"FROM,omitempty" validate:"omitempty"`
-		CallStatus int    `json:"call_status" validate:"required"`
-		CallResult string `json:"call_result,omitempty" validate:"omitempty"`
-		Text       string `json:"text,omitempty" validate:"omitempty"`
+		UNIQ       string     `json:"UNIQ" validate:"required"`
+		LINK       string     `json:"LINK" validate:"required"`
+		PHONE      string     `json:"PHONE" validate:"required"`
+		DURATION   int        `json:"DURATION" validate:"required"`
+		SRC        string     `json:"SRC" validate:"required"`
+		FROM       string     `json:"FROM,omitempty" validate:"omitempty"`
+		CallStatus CallStatus `json:"call_status" validate:"oneof=1 2 3 4 5 6 7"`
+		CallResult string     `json:"call_result,omitempty" validate:"omitempty"`
+		Text       string     `json:"text,omitempty" validate:"omitempty"`
 	}
 
 	NoteAdd struct {
-		ElementID         int                 `json:"element_id" validate:"required"`
-		ElementType       int                 `json:"element_type" validate:"oneof=1 2 3 4 12"`
-		Text              string              `json:"text,omitempty" validate:"omitempty"`
-		NoteType          int                 `json:"note_type" validate:"omitempty"`
-		CreatedAt         string              `json:"created_at,omitempty" validate:"omitempty"`
-		UpdatedAt         int                 `json:"updated_at,omitempty" validate:"omitempty"`
-		ResponsibleUserID int                 `json:"responsible_user_id,omitempty" validate:"omitempty"`
-		CreatedBy         int                 `json:"created_by,omitempty" validate:"omitempty"`
-		Params            *NotePostParameters `json:"params,omitempty" validate:"omitempty"`
+		ElementID         int        `json:"element_id" validate:"required"`
+		ElementType       int        `json:"element_type" validate:"oneof=1 2 3 4 12"`
+		Text              string     `json:"text,omitempty" validate:"omitempty"`
+		NoteType          NoteKind   `json:"note_type" validate:"omitempty"`
+		CreatedAt         Timestamp  `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp  `json:"updated_at,omitempty" validate:"omitempty"`
+		ResponsibleUserID int        `json:"responsible_user_id,omitempty" validate:"omitempty"`
+		CreatedBy         int        `json:"created_by,omitempty" validate:"omitempty"`
+		Params            NoteParams `json:"params,omitempty" validate:"omitempty"`
+		RequestID         int        `json:"request_id,omitempty" validate:"omitempty"`
+	}
+
+	NoteUpdate struct {
+		ID                int        `json:"id,string" validate:"required"`
+		ElementID         int        `json:"element_id,omitempty" validate:"omitempty"`
+		ElementType       int        `json:"element_type,omitempty" validate:"omitempty,oneof=1 2 3 4 12"`
+		Text              string     `json:"text,omitempty" validate:"omitempty"`
+		NoteType          NoteKind   `json:"note_type,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp  `json:"updated_at" validate:"required"`
+		ResponsibleUserID int        `json:"responsible_user_id,omitempty" validate:"omitempty"`
+		Params            NoteParams `json:"params,omitempty" validate:"omitempty"`
 	}
 
 	AddNoteRequest struct {
 		Add []*NoteAdd `json:"add" validate:"required"`
 	}
 
+	UpdateNoteRequest struct {
+		Update []*NoteUpdate `json:"update" validate:"required,dive,required"`
+	}
+
 	GetNoteResponse struct {
 		Links    *Links `json:"_links" validate:"omitempty"`
 		Embedded struct {
@@ -63,17 +87,18 @@ type (
 		ElementID         int             `json:"element_id" validate:"required"`
 		ElementType       int             `json:"element_type" validate:"oneof=1 2 3 4 12"`
 		Text              string          `json:"text" validate:"required"`
-		NoteType          int             `json:"note_type" validate:"required"`
-		CreatedAt         int             `json:"created_at" validate:"required"`
-		UpdatedAt         int             `json:"updated_at" validate:"required"`
+		NoteType          NoteKind        `json:"note_type" validate:"required"`
+		CreatedAt         Timestamp       `json:"created_at" validate:"required"`
+		UpdatedAt         Timestamp       `json:"updated_at" validate:"required"`
 		ResponsibleUserID int             `json:"responsible_user_id" validate:"required"`
 		Attachment        string          `json:"attachment" validate:"omitempty"`
 		Parameters        *NoteParameters `json:"params,omitempty" validate:"omitempty"`
+		RawParameters     json.RawMessage `json:"-" validate:"omitempty"`
 		Links             *Links          `json:"_links" validate:"required"`
 	}
 
 	NoteParameters struct {
-		Text    string `json:"TEXT" validate:"required"`
+		Text    string `json:"TEXT" validate:"omitempty"`
 		Service string `json:"service" validate:"omitempty"`
 		HTML    string `json:"HTML" validate:"omitempty"`
 	}
@@ -96,6 +121,12 @@ const (
 	LeadNoteType    NoteRequestType = "lead"
 	CompanyNoteType NoteRequestType = "company"
 	TaskNoteType    NoteRequestType = "task"
+
+	ContactNoteElementType  = 1
+	LeadNoteElementType     = 2
+	CompanyNoteElementType  = 3
+	TaskNoteElementType     = 4
+	CustomerNoteElementType = 12
 )
 
 func (c *Client) AddNote(ctx context.Context, note *NoteAdd) (int, error) {
@@ -103,7 +134,7 @@ func (c *Client) AddNote(ctx context.Context, note *NoteAdd) (int, error) {
 		return 0, err
 	}
 
-	resp, err := c.doPost(ctx, c.baseURL+notesURI, &AddNoteRequest{Add: []*NoteAdd{note}})
+	resp, err := c.doPost(ctx, c.baseURL+notesURI, newRetryableRequest(&AddNoteRequest{Add: []*NoteAdd{note}}, note.RequestID != 0))
 	if err != nil {
 		return 0, err
 	}
@@ -111,6 +142,56 @@ func (c *Client) AddNote(ctx context.Context, note *NoteAdd) (int, error) {
 	return c.getResponseID(resp)
 }
 
+func (c *Client) AddNotes(ctx context.Context, notes []*NoteAdd) ([]*BatchResult, error) {
+	if err := c.validator.Var(notes, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	requestIDs := make([]int, len(notes))
+	for i, note := range notes {
+		requestIDs[i] = note.RequestID
+	}
+
+	return c.postAddBatch(ctx, notesURI, requestIDs, func(from, to int) interface{} {
+		batch := make([]*NoteAdd, 0, to-from)
+		for i, note := range notes[from:to] {
+			item := *note
+			item.RequestID = requestIDs[from+i]
+			batch = append(batch, &item)
+		}
+
+		return &AddNoteRequest{Add: batch}
+	})
+}
+
+func (c *Client) UpdateNote(ctx context.Context, note *NoteUpdate) (int, error) {
+	if err := c.validator.Struct(note); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.doPost(ctx, c.baseURL+notesURI, &UpdateNoteRequest{Update: []*NoteUpdate{note}})
+	if err != nil {
+		return 0, err
+	}
+
+	return c.getResponseID(resp)
+}
+
+func (c *Client) UpdateNotes(ctx context.Context, notes []*NoteUpdate) ([]*BatchResult, error) {
+	if err := c.validator.Var(notes, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(notes))
+	for i, note := range notes {
+		ids[i] = note.ID
+	}
+
+	return c.postUpdateBatch(ctx, notesURI, ids, func(from, to int) interface{} {
+		return &UpdateNoteRequest{Update: notes[from:to]}
+	})
+}
+
 func (c *Client) GetNotes(ctx context.Context, reqParams *NoteRequestParams) ([]*Note, error) {
 	if err := c.validator.Struct(reqParams); err != nil {
 		return nil, err
@@ -133,8 +214,12 @@ func (c *Client) GetNotes(ctx context.Context, reqParams *NoteRequestParams) ([]
 	if reqParams.NoteType != nil {
 		addValues["note_type"] = joinIntSlice(reqParams.NoteType)
 	}
+	if reqParams.Filter != nil {
+		addDateRangeFilter(addValues, "filter[date_create]", reqParams.Filter.CreatedAt)
+		addDateRangeFilter(addValues, "filter[date_modify]", reqParams.Filter.UpdatedAt)
+	}
 
-	body, err := c.doGet(ctx, c.baseURL+notesURI, addValues)
+	body, err := c.doGetWithHeader(ctx, c.baseURL+notesURI, addValues, ifModifiedSinceHeader(reqParams.IfModifiedSince))
 	if err != nil {
 		return nil, err
 	}
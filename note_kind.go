@@ -0,0 +1,272 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+type (
+	NoteKind int
+
+	CallStatus int
+
+	// NoteParams is implemented by params of the note kinds, which could be created through the API
+	NoteParams interface {
+		noteParams()
+	}
+
+	SMSNoteParameters struct {
+		Text  string `json:"text" validate:"required"`
+		PHONE string `json:"PHONE" validate:"required"`
+	}
+
+	ServiceMessageNoteParameters struct {
+		Service string `json:"service" validate:"required"`
+		Text    string `json:"text" validate:"required"`
+	}
+
+	AttachmentNoteParameters struct {
+		OriginalName string `json:"original_name" validate:"required"`
+		Attachment   string `json:"attachment" validate:"required"`
+	}
+
+	GeolocationNoteParameters struct {
+		Text      string `json:"text" validate:"required"`
+		Address   string `json:"address" validate:"required"`
+		Longitude string `json:"longitude" validate:"required"`
+		Latitude  string `json:"latitude" validate:"required"`
+	}
+
+	InvoicePaidNoteParameters struct {
+		Text    string `json:"text" validate:"required"`
+		Service string `json:"service" validate:"required"`
+		IconURL string `json:"icon_url,omitempty" validate:"omitempty"`
+	}
+)
+
+const (
+	// geolocation and invoice paid notes have no fixed kinds in API v2, they are resolved by codes of account note types
+	GeolocationNoteCode = "GEOLOCATION"
+	InvoicePaidNoteCode = "INVOICE_PAID"
+)
+
+const (
+	LeadCreatedNoteKind       NoteKind = 1
+	ContactCreatedNoteKind    NoteKind = 2
+	LeadStatusChangedNoteKind NoteKind = 3
+	CommonNoteKind            NoteKind = 4
+	AttachmentNoteKind        NoteKind = 5
+	IncomingCallNoteKind      NoteKind = 10
+	OutgoingCallNoteKind      NoteKind = 11
+	CompanyCreatedNoteKind    NoteKind = 12
+	TaskResultNoteKind        NoteKind = 13
+	ServiceMessageNoteKind    NoteKind = 25
+	IncomingSMSNoteKind       NoteKind = 102
+	OutgoingSMSNoteKind       NoteKind = 103
+
+	VoicemailCallStatus     CallStatus = 1
+	CallBackLaterCallStatus CallStatus = 2
+	NotAvailableCallStatus  CallStatus = 3
+	SuccessfulCallStatus    CallStatus = 4
+	WrongNumberCallStatus   CallStatus = 5
+	NoAnswerCallStatus      CallStatus = 6
+	BusyCallStatus          CallStatus = 7
+)
+
+func (*NotePostParameters) noteParams()           {}
+func (*SMSNoteParameters) noteParams()            {}
+func (*ServiceMessageNoteParameters) noteParams() {}
+func (*AttachmentNoteParameters) noteParams()     {}
+func (*GeolocationNoteParameters) noteParams()    {}
+func (*InvoicePaidNoteParameters) noteParams()    {}
+
+func (s CallStatus) IsValid() bool {
+	return s >= VoicemailCallStatus && s <= BusyCallStatus
+}
+
+func NewCommonNote(elementType, elementID int, text string) *NoteAdd {
+	return &NoteAdd{ElementID: elementID, ElementType: elementType, NoteType: CommonNoteKind, Text: text}
+}
+
+func NewIncomingCallNote(elementType, elementID int, call *NotePostParameters) (*NoteAdd, error) {
+	return newCallNote(elementType, elementID, IncomingCallNoteKind, call)
+}
+
+func NewOutgoingCallNote(elementType, elementID int, call *NotePostParameters) (*NoteAdd, error) {
+	return newCallNote(elementType, elementID, OutgoingCallNoteKind, call)
+}
+
+func NewServiceMessageNote(elementType, elementID int, service, text string) *NoteAdd {
+	return &NoteAdd{
+		ElementID:   elementID,
+		ElementType: elementType,
+		NoteType:    ServiceMessageNoteKind,
+		Params:      &ServiceMessageNoteParameters{Service: service, Text: text},
+	}
+}
+
+func NewIncomingSMSNote(elementType, elementID int, phone, text string) *NoteAdd {
+	return newSMSNote(elementType, elementID, IncomingSMSNoteKind, phone, text)
+}
+
+func NewOutgoingSMSNote(elementType, elementID int, phone, text string) *NoteAdd {
+	return newSMSNote(elementType, elementID, OutgoingSMSNoteKind, phone, text)
+}
+
+func NewAttachmentNote(elementType, elementID int, originalName, attachment string) *NoteAdd {
+	return &NoteAdd{
+		ElementID:   elementID,
+		ElementType: elementType,
+		NoteType:    AttachmentNoteKind,
+		Params:      &AttachmentNoteParameters{OriginalName: originalName, Attachment: attachment},
+	}
+}
+
+// NewGeolocationNote creates a geolocation note with the kind resolved by GeolocationNoteCode
+func (m *AccountMetadata) NewGeolocationNote(ctx context.Context, elementType, elementID int, params *GeolocationNoteParameters) (*NoteAdd, error) {
+	return m.newCodeNote(ctx, elementType, elementID, GeolocationNoteCode, params)
+}
+
+// NewInvoicePaidNote creates an invoice paid note with the kind resolved by InvoicePaidNoteCode
+func (m *AccountMetadata) NewInvoicePaidNote(ctx context.Context, elementType, elementID int, params *InvoicePaidNoteParameters) (*NoteAdd, error) {
+	return m.newCodeNote(ctx, elementType, elementID, InvoicePaidNoteCode, params)
+}
+
+// NoteParams decodes params of the note like Note.Params, resolving kinds without fixed codes by account note types
+func (m *AccountMetadata) NoteParams(ctx context.Context, note *Note) (NoteParams, error) {
+	params, err := note.Params()
+	if !errors.Is(err, ErrUnsupportedNoteKind) {
+		return params, err
+	}
+
+	account, err := m.Account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	noteType, ok := account.Embedded.NoteTypes[strconv.Itoa(int(note.NoteType))]
+	if !ok {
+		return nil, ErrUnsupportedNoteKind
+	}
+
+	switch noteType.Code {
+	case GeolocationNoteCode:
+		params = new(GeolocationNoteParameters)
+	case InvoicePaidNoteCode:
+		params = new(InvoicePaidNoteParameters)
+	default:
+		return nil, ErrUnsupportedNoteKind
+	}
+
+	return decodeNoteParams(note.RawParameters, params)
+}
+
+func (m *AccountMetadata) newCodeNote(ctx context.Context, elementType, elementID int, code string, params NoteParams) (*NoteAdd, error) {
+	noteType, err := m.NoteTypeByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NoteAdd{ElementID: elementID, ElementType: elementType, NoteType: NoteKind(noteType.ID), Params: params}, nil
+}
+
+func newCallNote(elementType, elementID int, kind NoteKind, call *NotePostParameters) (*NoteAdd, error) {
+	if call == nil || !call.CallStatus.IsValid() {
+		return nil, ErrInvalidCallStatus
+	}
+
+	return &NoteAdd{ElementID: elementID, ElementType: elementType, NoteType: kind, Params: call}, nil
+}
+
+func newSMSNote(elementType, elementID int, kind NoteKind, phone, text string) *NoteAdd {
+	return &NoteAdd{
+		ElementID:   elementID,
+		ElementType: elementType,
+		NoteType:    kind,
+		Params:      &SMSNoteParameters{Text: text, PHONE: phone},
+	}
+}
+
+// Params decodes params of the note into the variant of its kind: *NotePostParameters for calls,
+// *SMSNoteParameters, *ServiceMessageNoteParameters and *AttachmentNoteParameters.
+// Geolocation and invoice paid notes are decoded by AccountMetadata.NoteParams.
+func (n *Note) Params() (NoteParams, error) {
+	var params NoteParams
+	switch n.NoteType {
+	case IncomingCallNoteKind, OutgoingCallNoteKind:
+		params = new(NotePostParameters)
+	case IncomingSMSNoteKind, OutgoingSMSNoteKind:
+		params = new(SMSNoteParameters)
+	case ServiceMessageNoteKind:
+		params = new(ServiceMessageNoteParameters)
+	case AttachmentNoteKind:
+		params = new(AttachmentNoteParameters)
+	default:
+		return nil, ErrUnsupportedNoteKind
+	}
+
+	return decodeNoteParams(n.RawParameters, params)
+}
+
+func decodeNoteParams(raw json.RawMessage, params NoteParams) (NoteParams, error) {
+	if len(raw) == 0 {
+		return params, nil
+	}
+
+	if err := json.Unmarshal(raw, params); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func (n *Note) UnmarshalJSON(data []byte) error {
+	type note Note
+	var raw struct {
+		*note
+		Params json.RawMessage `json:"params"`
+	}
+
+	raw.note = (*note)(n)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	n.Parameters, n.RawParameters = nil, nil
+
+	// empty params are serialized as an empty array instead of an empty object
+	if len(raw.Params) == 0 || raw.Params[0] != '{' {
+		return nil
+	}
+
+	n.RawParameters = raw.Params
+
+	// the generic params are kept for compatibility, typed ones are decoded by Params
+	params := new(NoteParameters)
+	if err := json.Unmarshal(raw.Params, params); err != nil {
+		return err
+	}
+	n.Parameters = params
+
+	return nil
+}
+
+func (n Note) MarshalJSON() ([]byte, error) {
+	type note Note
+	raw := struct {
+		note
+		Params json.RawMessage `json:"params,omitempty"`
+	}{note: note(n), Params: n.RawParameters}
+
+	if raw.Params == nil && n.Parameters != nil {
+		params, err := json.Marshal(n.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		raw.Params = params
+	}
+
+	return json.Marshal(raw)
+}
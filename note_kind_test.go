@@ -0,0 +1,118 @@
+package amocrm_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+func TestNoteParams(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		kind   amocrm.NoteKind
+		params string
+		want   amocrm.NoteParams
+		err    error
+	}{
+		{
+			name:   "incoming call",
+			kind:   amocrm.IncomingCallNoteKind,
+			params: `{"UNIQ":"u1","LINK":"https://example.com/1.mp3","PHONE":"+79001234567","DURATION":0,"SRC":"pbx","call_status":6}`,
+			want: &amocrm.NotePostParameters{
+				UNIQ: "u1", LINK: "https://example.com/1.mp3", PHONE: "+79001234567", SRC: "pbx", CallStatus: amocrm.NoAnswerCallStatus,
+			},
+		},
+		{
+			name:   "outgoing sms",
+			kind:   amocrm.OutgoingSMSNoteKind,
+			params: `{"text":"hello","PHONE":"+79001234567"}`,
+			want:   &amocrm.SMSNoteParameters{Text: "hello", PHONE: "+79001234567"},
+		},
+		{
+			name:   "service message",
+			kind:   amocrm.ServiceMessageNoteKind,
+			params: `{"service":"bot","text":"done"}`,
+			want:   &amocrm.ServiceMessageNoteParameters{Service: "bot", Text: "done"},
+		},
+		{
+			name:   "attachment",
+			kind:   amocrm.AttachmentNoteKind,
+			params: `{"original_name":"report.pdf","attachment":"abc.pdf"}`,
+			want:   &amocrm.AttachmentNoteParameters{OriginalName: "report.pdf", Attachment: "abc.pdf"},
+		},
+		{
+			name:   "empty params as array",
+			kind:   amocrm.IncomingSMSNoteKind,
+			params: `[]`,
+			want:   &amocrm.SMSNoteParameters{},
+		},
+		{
+			name:   "common note",
+			kind:   amocrm.CommonNoteKind,
+			params: `[]`,
+			err:    amocrm.ErrUnsupportedNoteKind,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			note := new(amocrm.Note)
+			data := `{"id":1,"note_type":` + strconv.Itoa(int(tt.kind)) + `,"params":` + tt.params + `}`
+			if err := json.Unmarshal([]byte(data), note); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := note.Params()
+			if err != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNoteKeepsGenericParameters(t *testing.T) {
+	for _, tt := range []struct {
+		params string
+		want   *amocrm.NoteParameters
+	}{
+		{`{"TEXT":"text"}`, &amocrm.NoteParameters{Text: "text"}},
+		{`{"HTML":"<b>html</b>"}`, &amocrm.NoteParameters{HTML: "<b>html</b>"}},
+		{`{"service":"bot"}`, &amocrm.NoteParameters{Service: "bot"}},
+		{`[]`, nil},
+	} {
+		note := new(amocrm.Note)
+		if err := json.Unmarshal([]byte(`{"id":1,"note_type":25,"params":`+tt.params+`}`), note); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(note.Parameters, tt.want) {
+			t.Errorf("params %s: expected %+v, got %+v", tt.params, tt.want, note.Parameters)
+		}
+	}
+}
+
+func TestCallNoteStatus(t *testing.T) {
+	for _, tt := range []struct {
+		call *amocrm.NotePostParameters
+		err  error
+	}{
+		{nil, amocrm.ErrInvalidCallStatus},
+		{&amocrm.NotePostParameters{}, amocrm.ErrInvalidCallStatus},
+		{&amocrm.NotePostParameters{CallStatus: amocrm.BusyCallStatus + 1}, amocrm.ErrInvalidCallStatus},
+		{&amocrm.NotePostParameters{CallStatus: amocrm.SuccessfulCallStatus}, nil},
+	} {
+		note, err := amocrm.NewIncomingCallNote(int(amocrm.LeadTaskElementType), 1, tt.call)
+		if err != tt.err {
+			t.Fatalf("call %+v: expected error %v, got %v", tt.call, tt.err, err)
+		}
+
+		if err == nil && note.NoteType != amocrm.IncomingCallNoteKind {
+			t.Fatalf("unexpected note kind %d", note.NoteType)
+		}
+	}
+}
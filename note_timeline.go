@@ -0,0 +1,46 @@
+package amocrm
+
+import (
+	"context"
+	"sort"
+)
+
+// GetLeadTimeline returns notes of the lead, its contacts and its company ordered by creation time
+func (c *Client) GetLeadTimeline(ctx context.Context, leadID int) ([]*Note, error) {
+	lead, err := c.getLead(ctx, leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	elementIDs := map[NoteRequestType][]int{
+		LeadNoteType:    {lead.ID},
+		ContactNoteType: lead.Contact.ID,
+	}
+	if lead.Company.ID != 0 {
+		elementIDs[CompanyNoteType] = []int{lead.Company.ID}
+	}
+
+	var notes []*Note
+	for noteType, ids := range elementIDs {
+		if len(ids) == 0 {
+			continue
+		}
+
+		it := c.IterateNotes(&NoteRequestParams{Type: noteType, ElementID: ids})
+		for it.Next(ctx) {
+			notes = append(notes, it.Note())
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].CreatedAt != notes[j].CreatedAt {
+			return notes[i].CreatedAt < notes[j].CreatedAt
+		}
+		return notes[i].ID < notes[j].ID
+	})
+
+	return notes, nil
+}
@@ -0,0 +1,249 @@
+package amocrm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	TokenStore interface {
+		GetToken(ctx context.Context) (*Token, error)
+		SetToken(ctx context.Context, token *Token) error
+	}
+
+	Token struct {
+		TokenType    string    `json:"token_type" validate:"required"`
+		AccessToken  string    `json:"access_token" validate:"required"`
+		RefreshToken string    `json:"refresh_token" validate:"required"`
+		ExpiresIn    int       `json:"expires_in" validate:"required"`
+		Expiry       time.Time `json:"expiry,omitempty" validate:"omitempty"`
+	}
+
+	OAuth2TokenRequest struct {
+		ClientID     string `json:"client_id" validate:"required"`
+		ClientSecret string `json:"client_secret" validate:"required"`
+		GrantType    string `json:"grant_type" validate:"required,oneof=authorization_code refresh_token"`
+		Code         string `json:"code,omitempty" validate:"required_without=RefreshToken"`
+		RefreshToken string `json:"refresh_token,omitempty" validate:"required_without=Code"`
+		RedirectURI  string `json:"redirect_uri" validate:"required"`
+	}
+
+	OAuth2ErrorResponse struct {
+		Hint   string `json:"hint" validate:"omitempty"`
+		Title  string `json:"title" validate:"omitempty"`
+		Type   string `json:"type" validate:"omitempty"`
+		Status int    `json:"status" validate:"omitempty"`
+		Detail string `json:"detail" validate:"omitempty"`
+	}
+
+	oauth2Config struct {
+		clientID     string
+		clientSecret string
+		redirectURI  string
+		store        TokenStore
+		mu           sync.Mutex
+	}
+
+	memoryTokenStore struct {
+		token *Token
+		mu    sync.RWMutex
+	}
+)
+
+const (
+	oauth2TokenURI = "/oauth2/access_token"
+
+	authorizationCodeGrantType = "authorization_code"
+	refreshTokenGrantType      = "refresh_token"
+
+	// refresh access token a bit earlier to avoid using it right at the moment of expiry
+	tokenExpiryDelta = 30 * time.Second
+)
+
+func WithOAuth2(clientID, clientSecret, redirectURI string, store TokenStore) ClientOption {
+	return func(c *Client) {
+		if store == nil {
+			store = NewMemoryTokenStore(nil)
+		}
+
+		c.oauth = &oauth2Config{
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			redirectURI:  redirectURI,
+			store:        store,
+		}
+	}
+}
+
+func NewMemoryTokenStore(token *Token) TokenStore {
+	return &memoryTokenStore{token: token}
+}
+
+func (s *memoryTokenStore) GetToken(_ context.Context) (*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.token, nil
+}
+
+func (s *memoryTokenStore) SetToken(_ context.Context, token *Token) error {
+	s.mu.Lock()
+	s.token = token
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (t *Token) Expired() bool {
+	if t.Expiry.IsZero() {
+		return false
+	}
+
+	return time.Now().Add(tokenExpiryDelta).After(t.Expiry)
+}
+
+func (o *oauth2Config) validate() error {
+	if o.clientID == "" {
+		return ErrEmptyClientID
+	}
+	if o.clientSecret == "" {
+		return ErrEmptyClientSecret
+	}
+	if o.redirectURI == "" {
+		return ErrEmptyRedirectURI
+	}
+
+	return nil
+}
+
+func (c *Client) ExchangeCode(ctx context.Context, code string) (*Token, error) {
+	if c.oauth == nil {
+		return nil, ErrOAuth2NotConfigured
+	}
+	if code == "" {
+		return nil, ErrEmptyAuthorizationCode
+	}
+
+	c.oauth.mu.Lock()
+	defer c.oauth.mu.Unlock()
+
+	return c.requestToken(ctx, &OAuth2TokenRequest{
+		ClientID:     c.oauth.clientID,
+		ClientSecret: c.oauth.clientSecret,
+		GrantType:    authorizationCodeGrantType,
+		Code:         code,
+		RedirectURI:  c.oauth.redirectURI,
+	})
+}
+
+func (c *Client) oauthToken(ctx context.Context) (*Token, error) {
+	token, err := c.oauth.store.GetToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		return nil, ErrEmptyToken
+	}
+
+	if token.Expired() {
+		return c.refreshOAuthToken(ctx, token)
+	}
+
+	return token, nil
+}
+
+func (c *Client) refreshOAuthToken(ctx context.Context, stale *Token) (*Token, error) {
+	c.oauth.mu.Lock()
+	defer c.oauth.mu.Unlock()
+
+	token, err := c.oauth.store.GetToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		return nil, ErrEmptyToken
+	}
+
+	// token has already been rotated by a concurrent caller or by another instance sharing the store
+	if stale != nil && token.AccessToken != stale.AccessToken && !token.Expired() {
+		return token, nil
+	}
+
+	if token.RefreshToken == "" {
+		return nil, ErrEmptyRefreshToken
+	}
+
+	return c.requestToken(ctx, &OAuth2TokenRequest{
+		ClientID:     c.oauth.clientID,
+		ClientSecret: c.oauth.clientSecret,
+		GrantType:    refreshTokenGrantType,
+		RefreshToken: token.RefreshToken,
+		RedirectURI:  c.oauth.redirectURI,
+	})
+}
+
+func (c *Client) requestToken(ctx context.Context, tokenRequest *OAuth2TokenRequest) (*Token, error) {
+	if err := c.validator.Struct(tokenRequest); err != nil {
+		return nil, err
+	}
+
+	reqBody, err := json.Marshal(tokenRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+oauth2TokenURI, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		httpErr := newHTTPError(resp, body)
+
+		errorResponse := new(OAuth2ErrorResponse)
+		if err := json.Unmarshal(body, errorResponse); err == nil && errorResponse.Hint != "" {
+			return nil, fmt.Errorf("%s: %s: %w", errorResponse.Title, errorResponse.Hint, httpErr)
+		}
+
+		return nil, httpErr
+	}
+
+	token := new(Token)
+	err = json.Unmarshal(body, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.validator.Struct(token); err != nil {
+		return nil, err
+	}
+
+	token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+
+	if err := c.oauth.store.SetToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,128 @@
+package amocrm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+// newOAuth2Server serves the token endpoint in front of the fake server, which accepts only the refreshed access token
+func newOAuth2Server(t *testing.T, refreshes *int) *httptest.Server {
+	t.Helper()
+
+	api := amocrmtest.NewServer(amocrmtest.WithAccessToken("fresh"))
+	t.Cleanup(api.Close)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", api.Config.Handler)
+	mux.HandleFunc("/oauth2/access_token", func(w http.ResponseWriter, r *http.Request) {
+		*refreshes++
+
+		tokenRequest := new(amocrm.OAuth2TokenRequest)
+		if err := json.NewDecoder(r.Body).Decode(tokenRequest); err != nil || tokenRequest.RefreshToken != "refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(&amocrm.OAuth2ErrorResponse{Title: "Bad Request", Hint: "Token has been revoked", Status: http.StatusBadRequest})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(&amocrm.Token{TokenType: "Bearer", AccessToken: "fresh", RefreshToken: "next", ExpiresIn: 86400})
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func TestOAuth2Refresh(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     *amocrm.Token
+		refreshes int
+		stored    string
+		err       error
+	}{
+		{
+			name:   "valid token",
+			token:  &amocrm.Token{AccessToken: "fresh", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+			stored: "fresh",
+		},
+		{
+			name:      "expired token",
+			token:     &amocrm.Token{AccessToken: "stale", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)},
+			refreshes: 1,
+			stored:    "fresh",
+		},
+		{
+			name:      "token about to expire",
+			token:     &amocrm.Token{AccessToken: "stale", RefreshToken: "refresh", Expiry: time.Now().Add(10 * time.Second)},
+			refreshes: 1,
+			stored:    "fresh",
+		},
+		{
+			name:      "revoked token",
+			token:     &amocrm.Token{AccessToken: "revoked", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+			refreshes: 1,
+			stored:    "fresh",
+		},
+		{
+			name:      "revoked refresh token",
+			token:     &amocrm.Token{AccessToken: "stale", RefreshToken: "revoked", Expiry: time.Now().Add(-time.Minute)},
+			refreshes: 1,
+			stored:    "stale",
+			err:       amocrm.ErrValidation,
+		},
+		{
+			name:   "empty refresh token",
+			token:  &amocrm.Token{AccessToken: "stale", Expiry: time.Now().Add(-time.Minute)},
+			stored: "stale",
+			err:    amocrm.ErrEmptyRefreshToken,
+		},
+		{
+			name: "empty token",
+			err:  amocrm.ErrEmptyToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshes := 0
+			ts := newOAuth2Server(t, &refreshes)
+			store := amocrm.NewMemoryTokenStore(tt.token)
+
+			c, err := amocrm.NewClient(ts.URL, "", "", amocrm.WithRateLimit(0, 0), amocrm.WithOAuth2("client", "secret", "https://example.com", store))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = c.GetPipelines(context.Background(), &amocrm.PipelineRequestParams{})
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if refreshes != tt.refreshes {
+				t.Fatalf("expected %d refreshes, got %d", tt.refreshes, refreshes)
+			}
+
+			token, err := store.GetToken(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if token != nil && token.AccessToken != tt.stored {
+				t.Fatalf("expected stored access token %q, got %q", tt.stored, token.AccessToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package amocrm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+func TestClientRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		rps      float64
+		burst    int
+		requests int
+		timeout  time.Duration
+		min, max time.Duration
+		err      error
+	}{
+		{name: "disabled", requests: 10, max: 100 * time.Millisecond},
+		{name: "within burst", rps: 10, burst: 5, requests: 5, max: 100 * time.Millisecond},
+		{name: "over burst", rps: 20, burst: 1, requests: 5, min: 200 * time.Millisecond},
+		{name: "zero burst", rps: 20, requests: 3, min: 100 * time.Millisecond},
+		{name: "context deadline", rps: 10, burst: 1, requests: 2, timeout: 50 * time.Millisecond, max: 100 * time.Millisecond, err: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newTestClient(t, amocrm.WithRateLimit(tt.rps, tt.burst))
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+
+			var err error
+			for i := 0; i < tt.requests && err == nil; i++ {
+				_, err = c.GetPipelines(ctx, &amocrm.PipelineRequestParams{})
+			}
+
+			elapsed := time.Since(start)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if elapsed < tt.min || tt.max > 0 && elapsed > tt.max {
+				t.Fatalf("unexpected duration of %d requests: %s", tt.requests, elapsed)
+			}
+		})
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	s := amocrmtest.NewServer(amocrmtest.WithRateLimit(1))
+	t.Cleanup(s.Close)
+
+	c := newServerClient(t, s, amocrm.WithRetryPolicy(nil))
+
+	_, err := c.GetPipelines(context.Background(), &amocrm.PipelineRequestParams{})
+	if !errors.Is(err, amocrm.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package amocrm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type (
+	RetryPolicy struct {
+		MaxAttempts          int
+		BaseDelay            time.Duration
+		MaxDelay             time.Duration
+		Jitter               float64
+		RetryableStatusCodes []int
+		RetryableErrorCodes  []int
+		// Methods limits retries to the listed HTTP methods, all methods are retried, when it is empty.
+		// POST requests are retried only when duplicates can be detected by request_id anyway.
+		Methods []string
+	}
+
+	idempotentRequest interface {
+		idempotent() bool
+	}
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseDelay   = 500 * time.Millisecond
+	defaultRetryMaxDelay    = 10 * time.Second
+	defaultRetryJitter      = 0.2
+)
+
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+		Jitter:      defaultRetryJitter,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableErrorCodes: []int{
+			RateLimitExceededCode,
+		},
+		Methods: []string{
+			http.MethodGet,
+			http.MethodPost,
+		},
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy, nil policy disables retries
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) allowsMethod(method string) bool {
+	if p == nil {
+		return false
+	}
+
+	if len(p.Methods) == 0 {
+		return true
+	}
+
+	for _, m := range p.Methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) retryable(ctx context.Context, err error, resp *http.Response, body []byte) bool {
+	if p == nil || ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		// only transport failures are worth retrying, but not the ones caused by request building or authorization
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+
+	if len(p.RetryableErrorCodes) == 0 {
+		return false
+	}
+
+	errorCode := responseErrorCode(body)
+	for _, code := range p.RetryableErrorCodes {
+		if errorCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		d -= time.Duration(p.Jitter * rand.Float64() * float64(d))
+	}
+
+	if resp != nil {
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > d {
+			d = retryAfter
+		}
+	}
+
+	return d
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package amocrm_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+	"github.com/ogi4i/amocrm-client/amocrmtest"
+)
+
+func TestRetryFailedRequests(t *testing.T) {
+	limitedPolicy := fastRetryPolicy()
+	limitedPolicy.RetryableErrorCodes = []int{amocrm.RateLimitExceededCode}
+
+	postOnlyPolicy := fastRetryPolicy()
+	postOnlyPolicy.Methods = []string{http.MethodPost}
+
+	tests := []struct {
+		name     string
+		policy   *amocrm.RetryPolicy
+		failure  *amocrmtest.Failure
+		requests int
+		fail     bool
+	}{
+		{
+			name:     "recovered",
+			policy:   fastRetryPolicy(),
+			failure:  &amocrmtest.Failure{StatusCode: http.StatusBadGateway, Times: 2},
+			requests: 3,
+		},
+		{
+			name:     "attempts exhausted",
+			policy:   fastRetryPolicy(),
+			failure:  &amocrmtest.Failure{StatusCode: http.StatusBadGateway, Times: 3},
+			requests: 3,
+			fail:     true,
+		},
+		{
+			name:     "status not retryable",
+			policy:   fastRetryPolicy(),
+			failure:  &amocrmtest.Failure{StatusCode: http.StatusInternalServerError},
+			requests: 1,
+			fail:     true,
+		},
+		{
+			name:     "error code with 200 OK",
+			policy:   limitedPolicy,
+			failure:  &amocrmtest.Failure{StatusCode: http.StatusOK, Body: amocrmtest.ResponseError(amocrm.RateLimitExceededCode, "Too many requests")},
+			requests: 2,
+		},
+		{
+			name:     "method not retryable",
+			policy:   postOnlyPolicy,
+			failure:  &amocrmtest.Failure{StatusCode: http.StatusBadGateway},
+			requests: 1,
+			fail:     true,
+		},
+		{
+			name:     "retries disabled",
+			failure:  &amocrmtest.Failure{StatusCode: http.StatusBadGateway},
+			requests: 1,
+			fail:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestClient(t, amocrm.WithRetryPolicy(tt.policy))
+			addTestLead(t, c, "retry")
+
+			tt.failure.Method, tt.failure.Path = http.MethodGet, leadsPath
+			s.Fail(tt.failure)
+
+			_, err := c.GetLeads(context.Background(), &amocrm.LeadRequestParams{})
+			if tt.fail && err == nil {
+				t.Fatal("expected error")
+			} else if !tt.fail && err != nil {
+				t.Fatal(err)
+			}
+
+			if n := countRequests(s, http.MethodGet, leadsPath); n != tt.requests {
+				t.Fatalf("expected %d requests, got %d", tt.requests, n)
+			}
+		})
+	}
+}
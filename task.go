@@ -3,7 +3,9 @@ package amocrm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strconv"
+	"time"
 )
 
 type (
@@ -17,13 +19,16 @@ type (
 		ResponsibleUserID int                `validate:"omitempty"`
 		Type              TaskRequestType    `validate:"omitempty,oneof=lead contact company customer"`
 		Filter            *TaskRequestFilter `validate:"omitempty"`
+		IfModifiedSince   time.Time          `validate:"omitempty"`
 	}
 
 	TaskRequestStatusFilter int
 
 	TaskRequestFilter struct {
-		Status   TaskRequestStatusFilter `validate:"omitempty,oneof=1 0"`
-		TaskType []int                   `validate:"omitempty,gt=0,dive,required"`
+		Status    TaskRequestStatusFilter `validate:"omitempty,oneof=1 2"`
+		TaskType  []int                   `validate:"omitempty,gt=0,dive,required"`
+		CreatedAt *DateRangeFilter        `validate:"omitempty"`
+		UpdatedAt *DateRangeFilter        `validate:"omitempty"`
 	}
 
 	TaskElementType int
@@ -31,11 +36,11 @@ type (
 	TaskAdd struct {
 		ElementID         int             `json:"element_id,string" validate:"required"`
 		ElementType       TaskElementType `json:"element_type,string" validate:"oneof=1 2 3 12"`
-		CompleteTill      int             `json:"complete_till,omitempty" validate:"omitempty"`
+		CompleteTill      Timestamp       `json:"complete_till,omitempty" validate:"omitempty"`
 		TaskType          int             `json:"task_type,string" validate:"required"`
 		Text              string          `json:"text,omitempty" validate:"omitempty"`
-		CreatedAt         int             `json:"created_at,string,omitempty" validate:"omitempty"`
-		UpdatedAt         int             `json:"updated_at,string,omitempty" validate:"omitempty"`
+		CreatedAt         Timestamp       `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp       `json:"updated_at,omitempty" validate:"omitempty"`
 		ResponsibleUserID int             `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
 		IsCompleted       bool            `json:"is_completed,omitempty" validate:"omitempty"`
 		CreatedBy         int             `json:"created_by,string,omitempty" validate:"omitempty"`
@@ -46,11 +51,11 @@ type (
 		ID                int             `json:"id,string" validate:"required"`
 		ElementID         int             `json:"element_id,string,omitempty" validate:"omitempty"`
 		ElementType       TaskElementType `json:"element_type,string,omitempty" validate:"omitempty,oneof=1 2 3 12"`
-		CompleteTill      int             `json:"complete_till,omitempty" validate:"omitempty"`
+		CompleteTill      Timestamp       `json:"complete_till,omitempty" validate:"omitempty"`
 		TaskType          int             `json:"task_type,string,omitempty" validate:"omitempty"`
 		Text              string          `json:"text" validate:"omitempty"`
-		CreatedAt         int             `json:"created_at,string,omitempty" validate:"omitempty"`
-		UpdatedAt         int             `json:"updated_at,string" validate:"required"`
+		CreatedAt         Timestamp       `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp       `json:"updated_at" validate:"required"`
 		ResponsibleUserID int             `json:"responsible_user_id,string,omitempty" validate:"omitempty"`
 		IsCompleted       bool            `json:"is_completed,omitempty" validate:"omitempty"`
 		CreatedBy         int             `json:"created_by,string,omitempty" validate:"omitempty"`
@@ -77,11 +82,11 @@ type (
 		ID                int             `json:"id" validate:"required"`
 		ElementID         int             `json:"element_id" validate:"required"`
 		ElementType       TaskElementType `json:"element_type" validate:"oneof=1 2 3 12"`
-		CompleteTillAt    int             `json:"complete_till_at" validate:"required"`
+		CompleteTillAt    Timestamp       `json:"complete_till_at" validate:"required"`
 		TaskType          int             `json:"task_type" validate:"required"`
 		Text              string          `json:"text" validate:"omitempty"`
-		CreatedAt         int             `json:"created_at" validate:"required"`
-		UpdatedAt         int             `json:"updated_at" validate:"required"`
+		CreatedAt         Timestamp       `json:"created_at" validate:"required"`
+		UpdatedAt         Timestamp       `json:"updated_at" validate:"required"`
 		ResponsibleUserID int             `json:"responsible_user_id" validate:"required"`
 		IsCompleted       bool            `json:"is_completed" validate:"omitempty"`
 		CreatedBy         int             `json:"created_by" validate:"required"`
@@ -108,8 +113,12 @@ const (
 	CompanyTaskType  TaskRequestType = "company"
 	CustomerTaskType TaskRequestType = "customer"
 
-	CompletedStatusTaskFilter  TaskRequestStatusFilter = 1
-	InProgressStatusTaskFilter TaskRequestStatusFilter = 0
+	CompletedStatusTaskFilter TaskRequestStatusFilter = 1
+	// in progress status is encoded as 0 in the API, which can not be distinguished from unset filter,
+	// so this value is sent as 0 instead
+	NotCompletedStatusTaskFilter TaskRequestStatusFilter = 2
+	// Deprecated: use NotCompletedStatusTaskFilter
+	InProgressStatusTaskFilter = NotCompletedStatusTaskFilter
 )
 
 func (c *Client) AddTask(ctx context.Context, task *TaskAdd) (int, error) {
@@ -117,7 +126,7 @@ func (c *Client) AddTask(ctx context.Context, task *TaskAdd) (int, error) {
 		return 0, err
 	}
 
-	resp, err := c.doPost(ctx, c.baseURL+tasksURI, &AddTaskRequest{Add: []*TaskAdd{task}})
+	resp, err := c.doPost(ctx, c.baseURL+tasksURI, newRetryableRequest(&AddTaskRequest{Add: []*TaskAdd{task}}, task.RequestID != 0))
 	if err != nil {
 		return 0, err
 	}
@@ -138,6 +147,43 @@ func (c *Client) UpdateTask(ctx context.Context, task *TaskUpdate) (int, error)
 	return c.getResponseID(resp)
 }
 
+func (c *Client) AddTasks(ctx context.Context, tasks []*TaskAdd) ([]*BatchResult, error) {
+	if err := c.validator.Var(tasks, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	requestIDs := make([]int, len(tasks))
+	for i, task := range tasks {
+		requestIDs[i] = task.RequestID
+	}
+
+	return c.postAddBatch(ctx, tasksURI, requestIDs, func(from, to int) interface{} {
+		batch := make([]*TaskAdd, 0, to-from)
+		for i, task := range tasks[from:to] {
+			item := *task
+			item.RequestID = requestIDs[from+i]
+			batch = append(batch, &item)
+		}
+
+		return &AddTaskRequest{Add: batch}
+	})
+}
+
+func (c *Client) UpdateTasks(ctx context.Context, tasks []*TaskUpdate) ([]*BatchResult, error) {
+	if err := c.validator.Var(tasks, "required,dive,required"); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	return c.postUpdateBatch(ctx, tasksURI, ids, func(from, to int) interface{} {
+		return &UpdateTaskRequest{Update: tasks[from:to]}
+	})
+}
+
 func (c *Client) GetTasks(ctx context.Context, reqParams *TaskRequestParams) ([]*Task, error) {
 	if err := c.validator.Struct(reqParams); err != nil {
 		return nil, err
@@ -162,8 +208,19 @@ func (c *Client) GetTasks(ctx context.Context, reqParams *TaskRequestParams) ([]
 	if reqParams.Type != "" {
 		addValues["type"] = string(reqParams.Type)
 	}
+	if reqParams.Filter != nil {
+		switch reqParams.Filter.Status {
+		case CompletedStatusTaskFilter:
+			addValues["filter[status]"] = "1"
+		case NotCompletedStatusTaskFilter:
+			addValues["filter[status]"] = "0"
+		}
+		addIndexedValues(addValues, "filter[task_type]", reqParams.Filter.TaskType)
+		addDateRangeFilter(addValues, "filter[date_create]", reqParams.Filter.CreatedAt)
+		addDateRangeFilter(addValues, "filter[date_modify]", reqParams.Filter.UpdatedAt)
+	}
 
-	body, err := c.doGet(ctx, c.baseURL+tasksURI, addValues)
+	body, err := c.doGetWithHeader(ctx, c.baseURL+tasksURI, addValues, ifModifiedSinceHeader(reqParams.IfModifiedSince))
 	if err != nil {
 		return nil, err
 	}
@@ -193,3 +250,25 @@ func (c *Client) GetTasks(ctx context.Context, reqParams *TaskRequestParams) ([]
 
 	return taskResponse.Embedded.Items, nil
 }
+
+func (c *Client) getTask(ctx context.Context, taskID int) (*Task, error) {
+	if taskID == 0 {
+		return nil, ErrEmptyEntityID
+	}
+
+	tasks, err := c.GetTasks(ctx, &TaskRequestParams{ID: []int{taskID}})
+	if errors.Is(err, ErrEmptyResponseItems) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		if task.ID == taskID {
+			return task, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
@@ -0,0 +1,125 @@
+package amocrm
+
+import (
+	"strconv"
+	"time"
+)
+
+type Timestamp int64
+
+const (
+	defaultBusinessDayEnd = 18 * time.Hour
+)
+
+func NewTimestamp(t time.Time) Timestamp {
+	if t.IsZero() {
+		return 0
+	}
+
+	return Timestamp(t.Unix())
+}
+
+func (t Timestamp) IsZero() bool {
+	return t == 0
+}
+
+func (t Timestamp) Time() time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(t), 0)
+}
+
+func (t Timestamp) In(loc *time.Location) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(t), 0).In(loc)
+}
+
+func (t Timestamp) String() string {
+	return strconv.FormatInt(int64(t), 10)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(t), 10)), nil
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	// amoCRM serializes timestamps either as numbers or as strings, empty values could be null, false or an empty string
+	if len(data) > 1 && data[0] == '"' {
+		data = data[1 : len(data)-1]
+	}
+
+	switch string(data) {
+	case "", "null", "false", "0":
+		*t = 0
+		return nil
+	}
+
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*t = Timestamp(n)
+
+	return nil
+}
+
+func WithTimezone(name string) ClientOption {
+	return func(c *Client) {
+		c.timezone = name
+	}
+}
+
+func WithBusinessDayEnd(hour, min int) ClientOption {
+	return func(c *Client) {
+		c.businessDayEnd = time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute
+	}
+}
+
+func (c *Client) Location() (*time.Location, error) {
+	c.mu.RLock()
+	timezone := c.timezone
+	c.mu.RUnlock()
+
+	if timezone == "" {
+		return nil, ErrUnknownTimezone
+	}
+
+	return time.LoadLocation(timezone)
+}
+
+func (c *Client) AccountTime(t Timestamp) (time.Time, error) {
+	loc, err := c.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.In(loc), nil
+}
+
+func (c *Client) EndOfDay(day time.Time) (Timestamp, error) {
+	loc, err := c.Location()
+	if err != nil {
+		return 0, err
+	}
+
+	// amoCRM shows tasks due at 23:59 as tasks for the whole day
+	y, m, d := day.In(loc).Date()
+	return NewTimestamp(time.Date(y, m, d, 23, 59, 0, 0, loc)), nil
+}
+
+func (c *Client) EndOfBusinessDay(day time.Time) (Timestamp, error) {
+	loc, err := c.Location()
+	if err != nil {
+		return 0, err
+	}
+
+	y, m, d := day.In(loc).Date()
+	hour, min := int(c.businessDayEnd/time.Hour), int(c.businessDayEnd%time.Hour/time.Minute)
+	return NewTimestamp(time.Date(y, m, d, hour, min, 0, 0, loc)), nil
+}
@@ -0,0 +1,120 @@
+package amocrm_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+func TestTimestampUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		ts   amocrm.Timestamp
+		fail bool
+	}{
+		{name: "number", data: `1600000000`, ts: 1600000000},
+		{name: "string", data: `"1600000000"`, ts: 1600000000},
+		{name: "zero", data: `0`},
+		{name: "zero string", data: `"0"`},
+		{name: "null", data: `null`},
+		{name: "false", data: `false`},
+		{name: "empty string", data: `""`},
+		{name: "date string", data: `"2020-09-13"`, fail: true},
+		{name: "float", data: `1600000000.5`, fail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := amocrm.Timestamp(1)
+			err := json.Unmarshal([]byte(tt.data), &ts)
+			if tt.fail {
+				if err == nil {
+					t.Fatalf("expected error, got %d", ts)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ts != tt.ts {
+				t.Fatalf("expected %d, got %d", tt.ts, ts)
+			}
+		})
+	}
+}
+
+func TestTimestampTime(t *testing.T) {
+	now := time.Now()
+
+	if ts := amocrm.NewTimestamp(time.Time{}); !ts.IsZero() || !ts.Time().IsZero() {
+		t.Fatalf("expected zero timestamp for zero time, got %d", ts)
+	}
+
+	if ts := amocrm.NewTimestamp(now); !ts.Time().Equal(now.Truncate(time.Second)) {
+		t.Fatalf("expected %s, got %s", now.Truncate(time.Second), ts.Time())
+	}
+}
+
+func TestEndOfDay(t *testing.T) {
+	day := time.Date(2020, 9, 13, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		opts             []amocrm.ClientOption
+		endOfDay         time.Time
+		endOfBusinessDay time.Time
+		err              error
+	}{
+		{
+			name:             "utc",
+			opts:             []amocrm.ClientOption{amocrm.WithTimezone("UTC")},
+			endOfDay:         time.Date(2020, 9, 13, 23, 59, 0, 0, time.UTC),
+			endOfBusinessDay: time.Date(2020, 9, 13, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "next day in account timezone",
+			opts:             []amocrm.ClientOption{amocrm.WithTimezone("Europe/Moscow")},
+			endOfDay:         time.Date(2020, 9, 14, 20, 59, 0, 0, time.UTC),
+			endOfBusinessDay: time.Date(2020, 9, 14, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "business day end",
+			opts:             []amocrm.ClientOption{amocrm.WithTimezone("UTC"), amocrm.WithBusinessDayEnd(19, 30)},
+			endOfDay:         time.Date(2020, 9, 13, 23, 59, 0, 0, time.UTC),
+			endOfBusinessDay: time.Date(2020, 9, 13, 19, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "unknown timezone",
+			err:  amocrm.ErrUnknownTimezone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := amocrm.NewClient("https://example.amocrm.ru", "login", "hash", tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			endOfDay, err := c.EndOfDay(day)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			businessDay, err := c.EndOfBusinessDay(day)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if endOfDay != amocrm.NewTimestamp(tt.endOfDay) || businessDay != amocrm.NewTimestamp(tt.endOfBusinessDay) {
+				t.Fatalf("expected %s and %s, got %s and %s", tt.endOfDay, tt.endOfBusinessDay, endOfDay.Time().UTC(), businessDay.Time().UTC())
+			}
+		})
+	}
+}
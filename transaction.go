@@ -0,0 +1,145 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+type (
+	TransactionRequestParams struct {
+		ID          []int `validate:"omitempty,gt=0,dive,required"`
+		CustomerID  []int `validate:"omitempty,gt=0,dive,required"`
+		LimitRows   int   `validate:"required_with=LimitOffset,lte=500"`
+		LimitOffset int   `validate:"omitempty"`
+	}
+
+	TransactionAdd struct {
+		CustomerID int       `json:"customer_id,string" validate:"required"`
+		Date       Timestamp `json:"date" validate:"required"`
+		Price      int       `json:"price,string" validate:"required"`
+		Comment    string    `json:"comment,omitempty" validate:"omitempty"`
+		NextPrice  int       `json:"next_price,string,omitempty" validate:"omitempty"`
+		NextDate   Timestamp `json:"next_date,omitempty" validate:"omitempty"`
+		RequestID  int       `json:"request_id,string,omitempty" validate:"omitempty"`
+	}
+
+	AddTransactionRequest struct {
+		Add []*TransactionAdd `json:"add" validate:"required,dive,required"`
+	}
+
+	DeleteTransactionRequest struct {
+		Delete []int `json:"delete" validate:"required,gt=0,dive,required"`
+	}
+
+	GetTransactionResponse struct {
+		Links    *Links `json:"_links" validate:"omitempty"`
+		Embedded struct {
+			Items []*Transaction `json:"items" validate:"required,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+		Response *AmoError `json:"response,omitempty" validate:"omitempty"`
+	}
+
+	Transaction struct {
+		ID         int       `json:"id" validate:"required"`
+		CustomerID int       `json:"customer_id" validate:"required"`
+		Date       Timestamp `json:"date" validate:"required"`
+		Price      int       `json:"price" validate:"omitempty"`
+		Comment    string    `json:"comment,omitempty" validate:"omitempty"`
+		CreatedBy  int       `json:"created_by" validate:"omitempty"`
+		CreatedAt  Timestamp `json:"created_at" validate:"omitempty"`
+		UpdatedAt  Timestamp `json:"updated_at" validate:"omitempty"`
+		AccountID  int       `json:"account_id" validate:"omitempty"`
+		IsDeleted  bool      `json:"is_deleted" validate:"omitempty"`
+		Links      *Links    `json:"_links" validate:"omitempty"`
+	}
+)
+
+func (c *Client) AddTransaction(ctx context.Context, transaction *TransactionAdd) (int, error) {
+	if err := c.validator.Struct(transaction); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.doPost(ctx, c.baseURL+transactionsURI, &AddTransactionRequest{Add: []*TransactionAdd{transaction}})
+	if err != nil {
+		return 0, err
+	}
+
+	return c.getResponseID(resp)
+}
+
+func (c *Client) DeleteTransaction(ctx context.Context, id int) error {
+	if id == 0 {
+		return ErrEmptyEntityID
+	}
+
+	resp, err := c.doPost(ctx, c.baseURL+transactionsURI, &DeleteTransactionRequest{Delete: []int{id}})
+	if err != nil {
+		return err
+	}
+
+	if len(resp) == 0 {
+		return nil
+	}
+
+	errorResponse := new(ErrorResponse)
+	if err := json.Unmarshal(resp, errorResponse); err != nil {
+		return err
+	}
+
+	if errorResponse.Response != nil {
+		return errorResponse.Response
+	}
+
+	return nil
+}
+
+func (c *Client) GetTransactions(ctx context.Context, reqParams *TransactionRequestParams) ([]*Transaction, error) {
+	if err := c.validator.Struct(reqParams); err != nil {
+		return nil, err
+	}
+
+	addValues := make(map[string]string)
+	if reqParams.ID != nil {
+		addValues["id"] = joinIntSlice(reqParams.ID)
+	}
+	if reqParams.CustomerID != nil {
+		addValues["customer_id"] = joinIntSlice(reqParams.CustomerID)
+	}
+	if reqParams.LimitRows != 0 {
+		addValues["limit_rows"] = strconv.Itoa(reqParams.LimitRows)
+		if reqParams.LimitOffset != 0 {
+			addValues["limit_offset"] = strconv.Itoa(reqParams.LimitOffset)
+		}
+	}
+
+	body, err := c.doGet(ctx, c.baseURL+transactionsURI, addValues)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	transactionResponse := new(GetTransactionResponse)
+	err = json.Unmarshal(body, transactionResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if transactionResponse.Response != nil {
+		return nil, transactionResponse.Response
+	}
+
+	err = c.validator.Struct(transactionResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transactionResponse.Embedded.Items) == 0 {
+		return nil, ErrEmptyResponseItems
+	}
+
+	return transactionResponse.Embedded.Items, nil
+}
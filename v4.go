@@ -0,0 +1,212 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type (
+	V4Client struct {
+		client *Client
+	}
+
+	V4With string
+
+	V4ListParams struct {
+		With              []V4With         `validate:"omitempty,dive,oneof=contacts leads companies customers catalog_elements loss_reason source_id only_deleted is_price_modified_by_robot"`
+		Page              int              `validate:"omitempty,gt=0"`
+		Limit             int              `validate:"omitempty,gt=0,lte=250"`
+		Query             string           `validate:"omitempty"`
+		ID                []int            `validate:"omitempty,gt=0,dive,required"`
+		ResponsibleUserID []int            `validate:"omitempty,gt=0,dive,required"`
+		PipelineID        []int            `validate:"omitempty,gt=0,dive,required"`
+		CreatedAt         *DateRangeFilter `validate:"omitempty"`
+		UpdatedAt         *DateRangeFilter `validate:"omitempty"`
+		ClosedAt          *DateRangeFilter `validate:"omitempty"`
+	}
+
+	V4Page struct {
+		Page  int      `json:"_page" validate:"omitempty"`
+		Links *V4Links `json:"_links" validate:"omitempty"`
+	}
+
+	V4Links struct {
+		Self  *V4Link `json:"self,omitempty" validate:"omitempty"`
+		Next  *V4Link `json:"next,omitempty" validate:"omitempty"`
+		Prev  *V4Link `json:"prev,omitempty" validate:"omitempty"`
+		First *V4Link `json:"first,omitempty" validate:"omitempty"`
+	}
+
+	V4Link struct {
+		Href string `json:"href" validate:"required"`
+	}
+
+	V4CustomFieldValues struct {
+		FieldID   int                   `json:"field_id,omitempty" validate:"required_without=FieldCode"`
+		FieldName string                `json:"field_name,omitempty" validate:"omitempty"`
+		FieldCode string                `json:"field_code,omitempty" validate:"omitempty"`
+		FieldType string                `json:"field_type,omitempty" validate:"omitempty"`
+		Values    []*V4CustomFieldValue `json:"values" validate:"required,dive,required"`
+	}
+
+	V4CustomFieldValue struct {
+		Value    interface{} `json:"value,omitempty" validate:"omitempty"`
+		EnumID   int         `json:"enum_id,omitempty" validate:"omitempty"`
+		EnumCode string      `json:"enum_code,omitempty" validate:"omitempty"`
+	}
+
+	V4Tag struct {
+		ID   int    `json:"id,omitempty" validate:"required_without=Name"`
+		Name string `json:"name,omitempty" validate:"omitempty"`
+	}
+
+	V4EntityLink struct {
+		ID     int      `json:"id" validate:"required"`
+		IsMain bool     `json:"is_main,omitempty" validate:"omitempty"`
+		Links  *V4Links `json:"_links,omitempty" validate:"omitempty"`
+	}
+)
+
+const (
+	v4LeadsURI     = "/api/v4/leads"
+	v4ContactsURI  = "/api/v4/contacts"
+	v4CompaniesURI = "/api/v4/companies"
+	v4TasksURI     = "/api/v4/tasks"
+
+	v4WithTypes = "contacts leads companies customers catalog_elements loss_reason source_id only_deleted is_price_modified_by_robot"
+
+	V4WithContacts               V4With = "contacts"
+	V4WithLeads                  V4With = "leads"
+	V4WithCompanies              V4With = "companies"
+	V4WithCustomers              V4With = "customers"
+	V4WithCatalogElements        V4With = "catalog_elements"
+	V4WithLossReason             V4With = "loss_reason"
+	V4WithSourceID               V4With = "source_id"
+	V4WithOnlyDeleted            V4With = "only_deleted"
+	V4WithIsPriceModifiedByRobot V4With = "is_price_modified_by_robot"
+)
+
+func (c *Client) V4() *V4Client {
+	return &V4Client{client: c}
+}
+
+func (p *V4Page) HasNext() bool {
+	return p.Links != nil && p.Links.Next != nil && p.Links.Next.Href != ""
+}
+
+func (v *V4Client) list(ctx context.Context, uri string, reqParams *V4ListParams, out interface{}) error {
+	if reqParams == nil {
+		reqParams = new(V4ListParams)
+	}
+
+	if err := v.client.validator.Struct(reqParams); err != nil {
+		return err
+	}
+
+	addValues := make(map[string]string)
+	if len(reqParams.With) > 0 {
+		addValues["with"] = joinV4With(reqParams.With)
+	}
+	if reqParams.Page != 0 {
+		addValues["page"] = strconv.Itoa(reqParams.Page)
+	}
+	if reqParams.Limit != 0 {
+		addValues["limit"] = strconv.Itoa(reqParams.Limit)
+	}
+	if reqParams.Query != "" {
+		addValues["query"] = reqParams.Query
+	}
+	addIndexedValues(addValues, "filter[id]", reqParams.ID)
+	addIndexedValues(addValues, "filter[responsible_user_id]", reqParams.ResponsibleUserID)
+	addIndexedValues(addValues, "filter[pipeline_id]", reqParams.PipelineID)
+	addDateRangeFilter(addValues, "filter[created_at]", reqParams.CreatedAt)
+	addDateRangeFilter(addValues, "filter[updated_at]", reqParams.UpdatedAt)
+	addDateRangeFilter(addValues, "filter[closed_at]", reqParams.ClosedAt)
+
+	return v.fetch(ctx, uri, addValues, out)
+}
+
+func (v *V4Client) fetch(ctx context.Context, uri string, values map[string]string, out interface{}) error {
+	body, err := v.client.doGet(ctx, v.client.baseURL+uri, values)
+	if err != nil {
+		return err
+	}
+
+	// amoCRM responds with 204 No Content, when nothing matches the request
+	if len(body) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func (v *V4Client) get(ctx context.Context, uri string, id int, with []V4With, out interface{}) error {
+	if id == 0 {
+		return ErrEmptyEntityID
+	}
+
+	if err := v.client.validator.Var(with, "omitempty,dive,oneof="+v4WithTypes); err != nil {
+		return err
+	}
+
+	addValues := make(map[string]string)
+	if len(with) > 0 {
+		addValues["with"] = joinV4With(with)
+	}
+
+	body, err := v.client.doGet(ctx, v.client.baseURL+uri+"/"+strconv.Itoa(id), addValues)
+	if err != nil {
+		return err
+	}
+
+	if len(body) == 0 {
+		return ErrNotFound
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func (v *V4Client) create(ctx context.Context, uri string, in interface{}, out interface{}) error {
+	if err := v.client.validator.Var(in, "required,gt=0,lte=250,dive,required"); err != nil {
+		return err
+	}
+
+	body, err := v.client.doPost(ctx, v.client.baseURL+uri, in)
+	if err != nil {
+		return err
+	}
+
+	if len(body) == 0 {
+		return ErrEmptyResponseItems
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func (v *V4Client) update(ctx context.Context, uri string, in interface{}, out interface{}) error {
+	if err := v.client.validator.Var(in, "required,gt=0,lte=250,dive,required"); err != nil {
+		return err
+	}
+
+	body, err := v.client.doPatch(ctx, v.client.baseURL+uri, in)
+	if err != nil {
+		return err
+	}
+
+	if len(body) == 0 {
+		return ErrEmptyResponseItems
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func joinV4With(with []V4With) string {
+	s := make([]string, 0, len(with))
+	for _, w := range with {
+		s = append(s, string(w))
+	}
+
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,87 @@
+package amocrm
+
+import "context"
+
+type (
+	V4CompaniesService struct {
+		v4 *V4Client
+	}
+
+	V4Company struct {
+		ID                 int                    `json:"id,omitempty" validate:"omitempty"`
+		Name               string                 `json:"name,omitempty" validate:"omitempty"`
+		ResponsibleUserID  int                    `json:"responsible_user_id,omitempty" validate:"omitempty"`
+		GroupID            int                    `json:"group_id,omitempty" validate:"omitempty"`
+		CreatedBy          int                    `json:"created_by,omitempty" validate:"omitempty"`
+		UpdatedBy          int                    `json:"updated_by,omitempty" validate:"omitempty"`
+		CreatedAt          Timestamp              `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt          Timestamp              `json:"updated_at,omitempty" validate:"omitempty"`
+		ClosestTaskAt      Timestamp              `json:"closest_task_at,omitempty" validate:"omitempty"`
+		IsDeleted          bool                   `json:"is_deleted,omitempty" validate:"omitempty"`
+		CustomFieldsValues []*V4CustomFieldValues `json:"custom_fields_values,omitempty" validate:"omitempty,dive,required"`
+		AccountID          int                    `json:"account_id,omitempty" validate:"omitempty"`
+		RequestID          string                 `json:"request_id,omitempty" validate:"omitempty"`
+		Links              *V4Links               `json:"_links,omitempty" validate:"omitempty"`
+		Embedded           *V4CompanyEmbedded     `json:"_embedded,omitempty" validate:"omitempty"`
+	}
+
+	V4CompanyEmbedded struct {
+		Tags      []*V4Tag        `json:"tags,omitempty" validate:"omitempty,dive,required"`
+		Contacts  []*V4EntityLink `json:"contacts,omitempty" validate:"omitempty,dive,required"`
+		Leads     []*V4EntityLink `json:"leads,omitempty" validate:"omitempty,dive,required"`
+		Customers []*V4EntityLink `json:"customers,omitempty" validate:"omitempty,dive,required"`
+	}
+
+	V4CompanyList struct {
+		V4Page
+		Embedded struct {
+			Companies []*V4Company `json:"companies" validate:"omitempty,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+	}
+)
+
+func (v *V4Client) Companies() *V4CompaniesService {
+	return &V4CompaniesService{v4: v}
+}
+
+func (s *V4CompaniesService) List(ctx context.Context, reqParams *V4ListParams) (*V4CompanyList, error) {
+	list := new(V4CompanyList)
+	if err := s.v4.list(ctx, v4CompaniesURI, reqParams, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *V4CompaniesService) Get(ctx context.Context, id int, with ...V4With) (*V4Company, error) {
+	company := new(V4Company)
+	if err := s.v4.get(ctx, v4CompaniesURI, id, with, company); err != nil {
+		return nil, err
+	}
+
+	return company, nil
+}
+
+func (s *V4CompaniesService) Create(ctx context.Context, companies []*V4Company) ([]*V4Company, error) {
+	list := new(V4CompanyList)
+	if err := s.v4.create(ctx, v4CompaniesURI, companies, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Companies, nil
+}
+
+func (s *V4CompaniesService) Update(ctx context.Context, companies []*V4Company) ([]*V4Company, error) {
+	for _, company := range companies {
+		if company != nil && company.ID == 0 {
+			return nil, ErrEmptyEntityID
+		}
+	}
+
+	list := new(V4CompanyList)
+	if err := s.v4.update(ctx, v4CompaniesURI, companies, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Companies, nil
+}
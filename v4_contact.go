@@ -0,0 +1,89 @@
+package amocrm
+
+import "context"
+
+type (
+	V4ContactsService struct {
+		v4 *V4Client
+	}
+
+	V4Contact struct {
+		ID                 int                    `json:"id,omitempty" validate:"omitempty"`
+		Name               string                 `json:"name,omitempty" validate:"omitempty"`
+		FirstName          string                 `json:"first_name,omitempty" validate:"omitempty"`
+		LastName           string                 `json:"last_name,omitempty" validate:"omitempty"`
+		ResponsibleUserID  int                    `json:"responsible_user_id,omitempty" validate:"omitempty"`
+		GroupID            int                    `json:"group_id,omitempty" validate:"omitempty"`
+		CreatedBy          int                    `json:"created_by,omitempty" validate:"omitempty"`
+		UpdatedBy          int                    `json:"updated_by,omitempty" validate:"omitempty"`
+		CreatedAt          Timestamp              `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt          Timestamp              `json:"updated_at,omitempty" validate:"omitempty"`
+		ClosestTaskAt      Timestamp              `json:"closest_task_at,omitempty" validate:"omitempty"`
+		IsDeleted          bool                   `json:"is_deleted,omitempty" validate:"omitempty"`
+		CustomFieldsValues []*V4CustomFieldValues `json:"custom_fields_values,omitempty" validate:"omitempty,dive,required"`
+		AccountID          int                    `json:"account_id,omitempty" validate:"omitempty"`
+		RequestID          string                 `json:"request_id,omitempty" validate:"omitempty"`
+		Links              *V4Links               `json:"_links,omitempty" validate:"omitempty"`
+		Embedded           *V4ContactEmbedded     `json:"_embedded,omitempty" validate:"omitempty"`
+	}
+
+	V4ContactEmbedded struct {
+		Tags      []*V4Tag        `json:"tags,omitempty" validate:"omitempty,dive,required"`
+		Leads     []*V4EntityLink `json:"leads,omitempty" validate:"omitempty,dive,required"`
+		Companies []*V4EntityLink `json:"companies,omitempty" validate:"omitempty,dive,required"`
+		Customers []*V4EntityLink `json:"customers,omitempty" validate:"omitempty,dive,required"`
+	}
+
+	V4ContactList struct {
+		V4Page
+		Embedded struct {
+			Contacts []*V4Contact `json:"contacts" validate:"omitempty,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+	}
+)
+
+func (v *V4Client) Contacts() *V4ContactsService {
+	return &V4ContactsService{v4: v}
+}
+
+func (s *V4ContactsService) List(ctx context.Context, reqParams *V4ListParams) (*V4ContactList, error) {
+	list := new(V4ContactList)
+	if err := s.v4.list(ctx, v4ContactsURI, reqParams, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *V4ContactsService) Get(ctx context.Context, id int, with ...V4With) (*V4Contact, error) {
+	contact := new(V4Contact)
+	if err := s.v4.get(ctx, v4ContactsURI, id, with, contact); err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+func (s *V4ContactsService) Create(ctx context.Context, contacts []*V4Contact) ([]*V4Contact, error) {
+	list := new(V4ContactList)
+	if err := s.v4.create(ctx, v4ContactsURI, contacts, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Contacts, nil
+}
+
+func (s *V4ContactsService) Update(ctx context.Context, contacts []*V4Contact) ([]*V4Contact, error) {
+	for _, contact := range contacts {
+		if contact != nil && contact.ID == 0 {
+			return nil, ErrEmptyEntityID
+		}
+	}
+
+	list := new(V4ContactList)
+	if err := s.v4.update(ctx, v4ContactsURI, contacts, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Contacts, nil
+}
@@ -0,0 +1,99 @@
+package amocrm
+
+import "context"
+
+type (
+	V4LeadsService struct {
+		v4 *V4Client
+	}
+
+	V4Lead struct {
+		ID                 int                    `json:"id,omitempty" validate:"omitempty"`
+		Name               string                 `json:"name,omitempty" validate:"omitempty"`
+		Price              int                    `json:"price,omitempty" validate:"omitempty"`
+		ResponsibleUserID  int                    `json:"responsible_user_id,omitempty" validate:"omitempty"`
+		GroupID            int                    `json:"group_id,omitempty" validate:"omitempty"`
+		StatusID           int                    `json:"status_id,omitempty" validate:"omitempty"`
+		PipelineID         int                    `json:"pipeline_id,omitempty" validate:"omitempty"`
+		LossReasonID       int                    `json:"loss_reason_id,omitempty" validate:"omitempty"`
+		SourceID           int                    `json:"source_id,omitempty" validate:"omitempty"`
+		CreatedBy          int                    `json:"created_by,omitempty" validate:"omitempty"`
+		UpdatedBy          int                    `json:"updated_by,omitempty" validate:"omitempty"`
+		CreatedAt          Timestamp              `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt          Timestamp              `json:"updated_at,omitempty" validate:"omitempty"`
+		ClosedAt           Timestamp              `json:"closed_at,omitempty" validate:"omitempty"`
+		ClosestTaskAt      Timestamp              `json:"closest_task_at,omitempty" validate:"omitempty"`
+		IsDeleted          bool                   `json:"is_deleted,omitempty" validate:"omitempty"`
+		CustomFieldsValues []*V4CustomFieldValues `json:"custom_fields_values,omitempty" validate:"omitempty,dive,required"`
+		Score              int                    `json:"score,omitempty" validate:"omitempty"`
+		AccountID          int                    `json:"account_id,omitempty" validate:"omitempty"`
+		RequestID          string                 `json:"request_id,omitempty" validate:"omitempty"`
+		Links              *V4Links               `json:"_links,omitempty" validate:"omitempty"`
+		Embedded           *V4LeadEmbedded        `json:"_embedded,omitempty" validate:"omitempty"`
+	}
+
+	V4LeadEmbedded struct {
+		Tags       []*V4Tag        `json:"tags,omitempty" validate:"omitempty,dive,required"`
+		Contacts   []*V4EntityLink `json:"contacts,omitempty" validate:"omitempty,dive,required"`
+		Companies  []*V4EntityLink `json:"companies,omitempty" validate:"omitempty,dive,required"`
+		LossReason []*V4LossReason `json:"loss_reason,omitempty" validate:"omitempty,dive,required"`
+	}
+
+	V4LossReason struct {
+		ID   int    `json:"id" validate:"required"`
+		Name string `json:"name" validate:"required"`
+	}
+
+	V4LeadList struct {
+		V4Page
+		Embedded struct {
+			Leads []*V4Lead `json:"leads" validate:"omitempty,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+	}
+)
+
+func (v *V4Client) Leads() *V4LeadsService {
+	return &V4LeadsService{v4: v}
+}
+
+func (s *V4LeadsService) List(ctx context.Context, reqParams *V4ListParams) (*V4LeadList, error) {
+	list := new(V4LeadList)
+	if err := s.v4.list(ctx, v4LeadsURI, reqParams, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *V4LeadsService) Get(ctx context.Context, id int, with ...V4With) (*V4Lead, error) {
+	lead := new(V4Lead)
+	if err := s.v4.get(ctx, v4LeadsURI, id, with, lead); err != nil {
+		return nil, err
+	}
+
+	return lead, nil
+}
+
+func (s *V4LeadsService) Create(ctx context.Context, leads []*V4Lead) ([]*V4Lead, error) {
+	list := new(V4LeadList)
+	if err := s.v4.create(ctx, v4LeadsURI, leads, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Leads, nil
+}
+
+func (s *V4LeadsService) Update(ctx context.Context, leads []*V4Lead) ([]*V4Lead, error) {
+	for _, lead := range leads {
+		if lead != nil && lead.ID == 0 {
+			return nil, ErrEmptyEntityID
+		}
+	}
+
+	list := new(V4LeadList)
+	if err := s.v4.update(ctx, v4LeadsURI, leads, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Leads, nil
+}
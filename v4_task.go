@@ -0,0 +1,87 @@
+package amocrm
+
+import "context"
+
+type (
+	V4TasksService struct {
+		v4 *V4Client
+	}
+
+	V4Task struct {
+		ID                int           `json:"id,omitempty" validate:"omitempty"`
+		CreatedBy         int           `json:"created_by,omitempty" validate:"omitempty"`
+		UpdatedBy         int           `json:"updated_by,omitempty" validate:"omitempty"`
+		CreatedAt         Timestamp     `json:"created_at,omitempty" validate:"omitempty"`
+		UpdatedAt         Timestamp     `json:"updated_at,omitempty" validate:"omitempty"`
+		ResponsibleUserID int           `json:"responsible_user_id,omitempty" validate:"omitempty"`
+		GroupID           int           `json:"group_id,omitempty" validate:"omitempty"`
+		EntityID          int           `json:"entity_id,omitempty" validate:"omitempty"`
+		EntityType        string        `json:"entity_type,omitempty" validate:"omitempty,oneof=leads contacts companies customers"`
+		IsCompleted       bool          `json:"is_completed,omitempty" validate:"omitempty"`
+		TaskTypeID        int           `json:"task_type_id,omitempty" validate:"omitempty"`
+		Text              string        `json:"text,omitempty" validate:"omitempty"`
+		Duration          int           `json:"duration,omitempty" validate:"omitempty"`
+		CompleteTill      Timestamp     `json:"complete_till,omitempty" validate:"omitempty"`
+		Result            *V4TaskResult `json:"result,omitempty" validate:"omitempty"`
+		AccountID         int           `json:"account_id,omitempty" validate:"omitempty"`
+		RequestID         string        `json:"request_id,omitempty" validate:"omitempty"`
+		Links             *V4Links      `json:"_links,omitempty" validate:"omitempty"`
+	}
+
+	V4TaskResult struct {
+		Text string `json:"text,omitempty" validate:"omitempty"`
+	}
+
+	V4TaskList struct {
+		V4Page
+		Embedded struct {
+			Tasks []*V4Task `json:"tasks" validate:"omitempty,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+	}
+)
+
+func (v *V4Client) Tasks() *V4TasksService {
+	return &V4TasksService{v4: v}
+}
+
+func (s *V4TasksService) List(ctx context.Context, reqParams *V4ListParams) (*V4TaskList, error) {
+	list := new(V4TaskList)
+	if err := s.v4.list(ctx, v4TasksURI, reqParams, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *V4TasksService) Get(ctx context.Context, id int) (*V4Task, error) {
+	task := new(V4Task)
+	if err := s.v4.get(ctx, v4TasksURI, id, nil, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (s *V4TasksService) Create(ctx context.Context, tasks []*V4Task) ([]*V4Task, error) {
+	list := new(V4TaskList)
+	if err := s.v4.create(ctx, v4TasksURI, tasks, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Tasks, nil
+}
+
+func (s *V4TasksService) Update(ctx context.Context, tasks []*V4Task) ([]*V4Task, error) {
+	for _, task := range tasks {
+		if task != nil && task.ID == 0 {
+			return nil, ErrEmptyEntityID
+		}
+	}
+
+	list := new(V4TaskList)
+	if err := s.v4.update(ctx, v4TasksURI, tasks, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Tasks, nil
+}
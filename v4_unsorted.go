@@ -0,0 +1,356 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+type (
+	V4UnsortedService struct {
+		v4 *V4Client
+	}
+
+	V4UnsortedCategory string
+
+	V4UnsortedListParams struct {
+		Page       int                  `validate:"omitempty,gt=0"`
+		Limit      int                  `validate:"omitempty,gt=0,lte=250"`
+		UID        []string             `validate:"omitempty,dive,required"`
+		Category   []V4UnsortedCategory `validate:"omitempty,dive,oneof=sip mail forms chats"`
+		PipelineID int                  `validate:"omitempty"`
+		// OrderBy sorts entries by created_at or updated_at, OrderDesc reverses the order
+		OrderBy   string `validate:"omitempty,oneof=created_at updated_at"`
+		OrderDesc bool   `validate:"omitempty"`
+	}
+
+	V4Unsorted struct {
+		UID        string              `json:"uid,omitempty" validate:"omitempty"`
+		SourceUID  string              `json:"source_uid" validate:"required"`
+		SourceName string              `json:"source_name" validate:"required"`
+		Category   V4UnsortedCategory  `json:"category,omitempty" validate:"omitempty"`
+		PipelineID int                 `json:"pipeline_id,omitempty" validate:"omitempty"`
+		CreatedAt  Timestamp           `json:"created_at,omitempty" validate:"omitempty"`
+		Metadata   json.RawMessage     `json:"metadata,omitempty" validate:"omitempty"`
+		AccountID  int                 `json:"account_id,omitempty" validate:"omitempty"`
+		RequestID  string              `json:"request_id,omitempty" validate:"omitempty"`
+		Links      *V4Links            `json:"_links,omitempty" validate:"omitempty"`
+		Embedded   *V4UnsortedEmbedded `json:"_embedded,omitempty" validate:"omitempty"`
+	}
+
+	V4UnsortedEmbedded struct {
+		Leads     []*V4Lead    `json:"leads,omitempty" validate:"omitempty,dive,required"`
+		Contacts  []*V4Contact `json:"contacts,omitempty" validate:"omitempty,dive,required"`
+		Companies []*V4Company `json:"companies,omitempty" validate:"omitempty,dive,required"`
+	}
+
+	V4UnsortedFormMetadata struct {
+		FormID     string    `json:"form_id" validate:"required"`
+		FormName   string    `json:"form_name" validate:"required"`
+		FormPage   string    `json:"form_page" validate:"required"`
+		IP         string    `json:"ip" validate:"required"`
+		FormSentAt Timestamp `json:"form_sent_at" validate:"required"`
+		Referer    string    `json:"referer,omitempty" validate:"omitempty"`
+		VisitorUID string    `json:"visitor_uid,omitempty" validate:"omitempty"`
+	}
+
+	V4UnsortedSIPMetadata struct {
+		From              string    `json:"from" validate:"required"`
+		Phone             string    `json:"phone" validate:"required"`
+		CalledAt          Timestamp `json:"called_at" validate:"required"`
+		Duration          int       `json:"duration" validate:"omitempty"`
+		Link              string    `json:"link,omitempty" validate:"omitempty"`
+		ServiceCode       string    `json:"service_code" validate:"required"`
+		UniqID            string    `json:"uniq,omitempty" validate:"omitempty"`
+		IsCallEventNeeded bool      `json:"is_call_event_needed,omitempty" validate:"omitempty"`
+	}
+
+	V4UnsortedForm struct {
+		SourceUID  string                  `validate:"required"`
+		SourceName string                  `validate:"required"`
+		PipelineID int                     `validate:"omitempty"`
+		CreatedAt  Timestamp               `validate:"omitempty"`
+		Metadata   *V4UnsortedFormMetadata `validate:"required"`
+		Leads      []*V4Lead               `validate:"omitempty,dive,required"`
+		Contacts   []*V4Contact            `validate:"omitempty,dive,required"`
+		Companies  []*V4Company            `validate:"omitempty,dive,required"`
+		RequestID  string                  `validate:"omitempty"`
+	}
+
+	V4UnsortedSIP struct {
+		SourceUID  string                 `validate:"required"`
+		SourceName string                 `validate:"required"`
+		PipelineID int                    `validate:"omitempty"`
+		CreatedAt  Timestamp              `validate:"omitempty"`
+		Metadata   *V4UnsortedSIPMetadata `validate:"required"`
+		Leads      []*V4Lead              `validate:"omitempty,dive,required"`
+		Contacts   []*V4Contact           `validate:"omitempty,dive,required"`
+		Companies  []*V4Company           `validate:"omitempty,dive,required"`
+		RequestID  string                 `validate:"omitempty"`
+	}
+
+	V4UnsortedAccept struct {
+		UserID   int `json:"user_id,omitempty" validate:"omitempty"`
+		StatusID int `json:"status_id,omitempty" validate:"omitempty"`
+	}
+
+	V4UnsortedDecline struct {
+		UserID int `json:"user_id,omitempty" validate:"omitempty"`
+	}
+
+	V4UnsortedLink struct {
+		Link struct {
+			EntityID   int    `json:"entity_id" validate:"required"`
+			EntityType string `json:"entity_type" validate:"required,eq=leads"`
+		} `json:"link" validate:"required"`
+		UserID int `json:"user_id,omitempty" validate:"omitempty"`
+	}
+
+	// V4UnsortedResult holds ids of the entities created or linked by accepting or linking an unsorted entry
+	V4UnsortedResult struct {
+		UID       string              `json:"uid" validate:"required"`
+		Embedded  *V4UnsortedEmbedded `json:"_embedded,omitempty" validate:"omitempty"`
+		AccountID int                 `json:"account_id,omitempty" validate:"omitempty"`
+	}
+
+	V4UnsortedList struct {
+		V4Page
+		Embedded struct {
+			Unsorted []*V4Unsorted `json:"unsorted" validate:"omitempty,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+	}
+
+	v4UnsortedRequest struct {
+		SourceUID  string              `json:"source_uid"`
+		SourceName string              `json:"source_name"`
+		PipelineID int                 `json:"pipeline_id,omitempty"`
+		CreatedAt  Timestamp           `json:"created_at,omitempty"`
+		Metadata   interface{}         `json:"metadata"`
+		Embedded   *V4UnsortedEmbedded `json:"_embedded,omitempty"`
+		RequestID  string              `json:"request_id,omitempty"`
+	}
+)
+
+const (
+	v4UnsortedURI      = "/api/v4/leads/unsorted"
+	v4UnsortedFormsURI = v4UnsortedURI + "/forms"
+	v4UnsortedSIPURI   = v4UnsortedURI + "/sip"
+
+	// amoCRM API does not allow to create mail entries, they only appear from the connected mailboxes
+	SIPUnsortedCategory   V4UnsortedCategory = "sip"
+	MailUnsortedCategory  V4UnsortedCategory = "mail"
+	FormsUnsortedCategory V4UnsortedCategory = "forms"
+	ChatsUnsortedCategory V4UnsortedCategory = "chats"
+)
+
+func (v *V4Client) Unsorted() *V4UnsortedService {
+	return &V4UnsortedService{v4: v}
+}
+
+func (s *V4UnsortedService) List(ctx context.Context, reqParams *V4UnsortedListParams) (*V4UnsortedList, error) {
+	if reqParams == nil {
+		reqParams = new(V4UnsortedListParams)
+	}
+
+	if err := s.v4.client.validator.Struct(reqParams); err != nil {
+		return nil, err
+	}
+
+	addValues := make(map[string]string)
+	if reqParams.Page != 0 {
+		addValues["page"] = strconv.Itoa(reqParams.Page)
+	}
+	if reqParams.Limit != 0 {
+		addValues["limit"] = strconv.Itoa(reqParams.Limit)
+	}
+	for i, uid := range reqParams.UID {
+		addValues["filter[uid]["+strconv.Itoa(i)+"]"] = uid
+	}
+	for i, category := range reqParams.Category {
+		addValues["filter[category]["+strconv.Itoa(i)+"]"] = string(category)
+	}
+	if reqParams.PipelineID != 0 {
+		addValues["filter[pipeline_id]"] = strconv.Itoa(reqParams.PipelineID)
+	}
+	if reqParams.OrderBy != "" {
+		order := "asc"
+		if reqParams.OrderDesc {
+			order = "desc"
+		}
+		addValues["order["+reqParams.OrderBy+"]"] = order
+	}
+
+	list := new(V4UnsortedList)
+	if err := s.v4.fetch(ctx, v4UnsortedURI, addValues, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *V4UnsortedService) Get(ctx context.Context, uid string) (*V4Unsorted, error) {
+	if uid == "" {
+		return nil, ErrEmptyEntityID
+	}
+
+	unsorted := new(V4Unsorted)
+	if err := s.v4.fetch(ctx, v4UnsortedURI+"/"+uid, nil, unsorted); err != nil {
+		return nil, err
+	}
+
+	if unsorted.UID == "" {
+		return nil, ErrNotFound
+	}
+
+	return unsorted, nil
+}
+
+func (s *V4UnsortedService) CreateForms(ctx context.Context, forms []*V4UnsortedForm) ([]*V4Unsorted, error) {
+	if err := s.v4.client.validator.Var(forms, "required,gt=0,lte=250,dive,required"); err != nil {
+		return nil, err
+	}
+
+	requests := make([]*v4UnsortedRequest, 0, len(forms))
+	for _, form := range forms {
+		requests = append(requests, &v4UnsortedRequest{
+			SourceUID:  form.SourceUID,
+			SourceName: form.SourceName,
+			PipelineID: form.PipelineID,
+			CreatedAt:  form.CreatedAt,
+			Metadata:   form.Metadata,
+			Embedded:   newV4UnsortedEmbedded(form.Leads, form.Contacts, form.Companies),
+			RequestID:  form.RequestID,
+		})
+	}
+
+	return s.create(ctx, v4UnsortedFormsURI, requests)
+}
+
+func (s *V4UnsortedService) CreateSIP(ctx context.Context, calls []*V4UnsortedSIP) ([]*V4Unsorted, error) {
+	if err := s.v4.client.validator.Var(calls, "required,gt=0,lte=250,dive,required"); err != nil {
+		return nil, err
+	}
+
+	requests := make([]*v4UnsortedRequest, 0, len(calls))
+	for _, call := range calls {
+		requests = append(requests, &v4UnsortedRequest{
+			SourceUID:  call.SourceUID,
+			SourceName: call.SourceName,
+			PipelineID: call.PipelineID,
+			CreatedAt:  call.CreatedAt,
+			Metadata:   call.Metadata,
+			Embedded:   newV4UnsortedEmbedded(call.Leads, call.Contacts, call.Companies),
+			RequestID:  call.RequestID,
+		})
+	}
+
+	return s.create(ctx, v4UnsortedSIPURI, requests)
+}
+
+func (s *V4UnsortedService) Accept(ctx context.Context, uid string, accept *V4UnsortedAccept) (*V4UnsortedResult, error) {
+	if accept == nil {
+		accept = new(V4UnsortedAccept)
+	}
+
+	return s.action(ctx, http.MethodPost, uid, "accept", accept)
+}
+
+func (s *V4UnsortedService) Decline(ctx context.Context, uid string, decline *V4UnsortedDecline) (*V4UnsortedResult, error) {
+	if decline == nil {
+		decline = new(V4UnsortedDecline)
+	}
+
+	// declining is the only unsorted action, which uses DELETE method
+	return s.action(ctx, http.MethodDelete, uid, "decline", decline)
+}
+
+func (s *V4UnsortedService) Link(ctx context.Context, uid string, leadID int) (*V4UnsortedResult, error) {
+	link := new(V4UnsortedLink)
+	link.Link.EntityID = leadID
+	link.Link.EntityType = "leads"
+
+	return s.action(ctx, http.MethodPost, uid, "link", link)
+}
+
+func (s *V4UnsortedService) create(ctx context.Context, uri string, requests []*v4UnsortedRequest) ([]*V4Unsorted, error) {
+	list := new(V4UnsortedList)
+	if err := s.v4.create(ctx, uri, requests, list); err != nil {
+		return nil, err
+	}
+
+	return list.Embedded.Unsorted, nil
+}
+
+func (s *V4UnsortedService) action(ctx context.Context, method string, uid string, action string, in interface{}) (*V4UnsortedResult, error) {
+	if uid == "" {
+		return nil, ErrEmptyEntityID
+	}
+
+	if err := s.v4.client.validator.Struct(in); err != nil {
+		return nil, err
+	}
+
+	body, err := s.v4.client.doJSON(ctx, method, s.v4.client.baseURL+v4UnsortedURI+"/"+uid+"/"+action, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) == 0 {
+		return nil, ErrEmptyResponseItems
+	}
+
+	result := new(V4UnsortedResult)
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func newV4UnsortedEmbedded(leads []*V4Lead, contacts []*V4Contact, companies []*V4Company) *V4UnsortedEmbedded {
+	if len(leads) == 0 && len(contacts) == 0 && len(companies) == 0 {
+		return nil
+	}
+
+	return &V4UnsortedEmbedded{Leads: leads, Contacts: contacts, Companies: companies}
+}
+
+func (e *V4UnsortedEmbedded) LeadIDs() []int {
+	if e == nil {
+		return nil
+	}
+
+	ids := make([]int, 0, len(e.Leads))
+	for _, lead := range e.Leads {
+		ids = append(ids, lead.ID)
+	}
+
+	return ids
+}
+
+func (e *V4UnsortedEmbedded) ContactIDs() []int {
+	if e == nil {
+		return nil
+	}
+
+	ids := make([]int, 0, len(e.Contacts))
+	for _, contact := range e.Contacts {
+		ids = append(ids, contact.ID)
+	}
+
+	return ids
+}
+
+func (e *V4UnsortedEmbedded) CompanyIDs() []int {
+	if e == nil {
+		return nil
+	}
+
+	ids := make([]int, 0, len(e.Companies))
+	for _, company := range e.Companies {
+		ids = append(ids, company.ID)
+	}
+
+	return ids
+}
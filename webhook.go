@@ -0,0 +1,202 @@
+package amocrm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+type (
+	WebhookEvent string
+
+	Webhook struct {
+		ID       int            `json:"id,omitempty" validate:"omitempty"`
+		URL      string         `json:"url" validate:"required"`
+		Result   bool           `json:"result,omitempty" validate:"omitempty"`
+		Disabled bool           `json:"disabled,omitempty" validate:"omitempty"`
+		Events   []WebhookEvent `json:"events,omitempty" validate:"omitempty,dive,required"`
+	}
+
+	WebhookSubscription struct {
+		URL    string         `json:"url" validate:"required,url"`
+		Events []WebhookEvent `json:"events,omitempty" validate:"omitempty,dive,required"`
+	}
+
+	SubscribeWebhookRequest struct {
+		Subscribe []*WebhookSubscription `json:"subscribe" validate:"required,dive,required"`
+	}
+
+	UnsubscribeWebhookRequest struct {
+		Unsubscribe []*WebhookSubscription `json:"unsubscribe" validate:"required,dive,required"`
+	}
+
+	GetWebhookResponse struct {
+		Links    *Links `json:"_links" validate:"omitempty"`
+		Embedded struct {
+			Items []*Webhook `json:"items" validate:"omitempty,dive,required"`
+		} `json:"_embedded" validate:"omitempty"`
+		Response *AmoError `json:"response" validate:"omitempty"`
+	}
+)
+
+const (
+	AddLeadWebhookEvent             WebhookEvent = "add_lead"
+	AddContactWebhookEvent          WebhookEvent = "add_contact"
+	AddCompanyWebhookEvent          WebhookEvent = "add_company"
+	AddCustomerWebhookEvent         WebhookEvent = "add_customer"
+	UpdateLeadWebhookEvent          WebhookEvent = "update_lead"
+	UpdateContactWebhookEvent       WebhookEvent = "update_contact"
+	UpdateCompanyWebhookEvent       WebhookEvent = "update_company"
+	UpdateCustomerWebhookEvent      WebhookEvent = "update_customer"
+	DeleteLeadWebhookEvent          WebhookEvent = "delete_lead"
+	DeleteContactWebhookEvent       WebhookEvent = "delete_contact"
+	DeleteCompanyWebhookEvent       WebhookEvent = "delete_company"
+	DeleteCustomerWebhookEvent      WebhookEvent = "delete_customer"
+	RestoreLeadWebhookEvent         WebhookEvent = "restore_lead"
+	RestoreContactWebhookEvent      WebhookEvent = "restore_contact"
+	RestoreCompanyWebhookEvent      WebhookEvent = "restore_company"
+	StatusLeadWebhookEvent          WebhookEvent = "status_lead"
+	ResponsibleLeadWebhookEvent     WebhookEvent = "responsible_lead"
+	ResponsibleContactWebhookEvent  WebhookEvent = "responsible_contact"
+	ResponsibleCompanyWebhookEvent  WebhookEvent = "responsible_company"
+	ResponsibleCustomerWebhookEvent WebhookEvent = "responsible_customer"
+	NoteLeadWebhookEvent            WebhookEvent = "note_lead"
+	NoteContactWebhookEvent         WebhookEvent = "note_contact"
+	NoteCompanyWebhookEvent         WebhookEvent = "note_company"
+	NoteCustomerWebhookEvent        WebhookEvent = "note_customer"
+	AddTaskWebhookEvent             WebhookEvent = "add_task"
+	UpdateTaskWebhookEvent          WebhookEvent = "update_task"
+	DeleteTaskWebhookEvent          WebhookEvent = "delete_task"
+	AddUnsortedWebhookEvent         WebhookEvent = "add_unsorted"
+	UpdateUnsortedWebhookEvent      WebhookEvent = "update_unsorted"
+	DeleteUnsortedWebhookEvent      WebhookEvent = "delete_unsorted"
+)
+
+var (
+	// WebhookEvents lists all events, which could be subscribed to and received by webhooks
+	WebhookEvents = []WebhookEvent{
+		AddLeadWebhookEvent, AddContactWebhookEvent, AddCompanyWebhookEvent, AddCustomerWebhookEvent,
+		UpdateLeadWebhookEvent, UpdateContactWebhookEvent, UpdateCompanyWebhookEvent, UpdateCustomerWebhookEvent,
+		DeleteLeadWebhookEvent, DeleteContactWebhookEvent, DeleteCompanyWebhookEvent, DeleteCustomerWebhookEvent,
+		RestoreLeadWebhookEvent, RestoreContactWebhookEvent, RestoreCompanyWebhookEvent,
+		StatusLeadWebhookEvent,
+		ResponsibleLeadWebhookEvent, ResponsibleContactWebhookEvent, ResponsibleCompanyWebhookEvent, ResponsibleCustomerWebhookEvent,
+		NoteLeadWebhookEvent, NoteContactWebhookEvent, NoteCompanyWebhookEvent, NoteCustomerWebhookEvent,
+		AddTaskWebhookEvent, UpdateTaskWebhookEvent, DeleteTaskWebhookEvent,
+		AddUnsortedWebhookEvent, UpdateUnsortedWebhookEvent, DeleteUnsortedWebhookEvent,
+	}
+
+	webhookEventTypes = joinWebhookEvents(WebhookEvents)
+)
+
+func (e WebhookEvent) IsValid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	body, err := c.doGet(ctx, c.baseURL+webhooksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	webhookResponse := new(GetWebhookResponse)
+	err = json.Unmarshal(body, webhookResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhookResponse.Response != nil {
+		return nil, webhookResponse.Response
+	}
+
+	if err := c.validator.Struct(webhookResponse); err != nil {
+		return nil, err
+	}
+
+	return webhookResponse.Embedded.Items, nil
+}
+
+func (c *Client) SubscribeWebhook(ctx context.Context, url string, events []WebhookEvent) (*Webhook, error) {
+	subscription := &WebhookSubscription{URL: url, Events: events}
+	if err := c.validator.Struct(subscription); err != nil {
+		return nil, err
+	}
+
+	if err := c.validator.Var(events, "required,gt=0,dive,oneof="+webhookEventTypes); err != nil {
+		return nil, err
+	}
+
+	body, err := c.doPost(ctx, c.baseURL+webhooksSubscribeURI, &SubscribeWebhookRequest{Subscribe: []*WebhookSubscription{subscription}})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.webhookResult(subscription, body)
+}
+
+// UnsubscribeWebhook removes given events from the webhook, empty events remove the webhook completely
+func (c *Client) UnsubscribeWebhook(ctx context.Context, url string, events ...WebhookEvent) (*Webhook, error) {
+	subscription := &WebhookSubscription{URL: url, Events: events}
+	if err := c.validator.Struct(subscription); err != nil {
+		return nil, err
+	}
+
+	if err := c.validator.Var(events, "omitempty,dive,oneof="+webhookEventTypes); err != nil {
+		return nil, err
+	}
+
+	body, err := c.doPost(ctx, c.baseURL+webhooksUnsubscribeURI, &UnsubscribeWebhookRequest{Unsubscribe: []*WebhookSubscription{subscription}})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.webhookResult(subscription, body)
+}
+
+func (c *Client) webhookResult(subscription *WebhookSubscription, body []byte) (*Webhook, error) {
+	// amoCRM responds with 204 No Content, when the request is accepted without a report
+	if len(body) == 0 {
+		return &Webhook{URL: subscription.URL, Result: true, Events: subscription.Events}, nil
+	}
+
+	webhookResponse := new(GetWebhookResponse)
+	err := json.Unmarshal(body, webhookResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhookResponse.Response != nil {
+		return nil, webhookResponse.Response
+	}
+
+	if len(webhookResponse.Embedded.Items) == 0 {
+		return nil, ErrEmptyResponseItems
+	}
+
+	// amoCRM rejects webhooks with unreachable urls, reporting it only in the result flag
+	webhook := webhookResponse.Embedded.Items[0]
+	if !webhook.Result {
+		return webhook, ErrWebhookRejected
+	}
+
+	return webhook, nil
+}
+
+func joinWebhookEvents(events []WebhookEvent) string {
+	s := make([]string, 0, len(events))
+	for _, e := range events {
+		s = append(s, string(e))
+	}
+
+	return strings.Join(s, " ")
+}
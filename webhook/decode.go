@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+type (
+	// node is a level of the bracket notation, e.g. leads[status][0][id]
+	node struct {
+		value    string
+		children map[string]*node
+	}
+
+	sliceItem struct {
+		index int
+		key   string
+	}
+)
+
+// ParsePayload decodes webhook form values with nested bracket notation keys into a typed payload
+func ParsePayload(values url.Values) (*Payload, error) {
+	root := &node{children: make(map[string]*node)}
+	for key, v := range values {
+		if len(v) == 0 {
+			continue
+		}
+
+		n := root
+		for _, part := range splitKey(key) {
+			child, ok := n.children[part]
+			if !ok {
+				child = &node{children: make(map[string]*node)}
+				n.children[part] = child
+			}
+			n = child
+		}
+		n.value = v[0]
+	}
+
+	payload := new(Payload)
+	if err := decodeNode(root, reflect.ValueOf(payload).Elem()); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func splitKey(key string) []string {
+	i := strings.IndexByte(key, '[')
+	if i < 0 {
+		return []string{key}
+	}
+
+	parts := []string{key[:i]}
+	for _, part := range strings.Split(key[i+1:], "[") {
+		parts = append(parts, strings.TrimSuffix(part, "]"))
+	}
+
+	return parts
+}
+
+func decodeNode(n *node, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeNode(n, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+
+			child, ok := n.children[name]
+			if !ok {
+				continue
+			}
+
+			if err := decodeNode(child, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		// lists are sent as maps with numeric keys, original keys are kept, since "1" and "01" are different keys
+		items := make([]sliceItem, 0, len(n.children))
+		seen := make(map[int]string, len(n.children))
+		for k := range n.children {
+			i, err := strconv.Atoi(k)
+			if err != nil {
+				continue
+			}
+			if prev, ok := seen[i]; ok {
+				return fmt.Errorf("%s, %s: %w", prev, k, amocrm.ErrInvalidWebhookPayload)
+			}
+			seen[i] = k
+			items = append(items, sliceItem{index: i, key: k})
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].index < items[j].index })
+
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeNode(n.children[item.key], slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.String:
+		v.SetString(n.value)
+	case reflect.Bool:
+		v.SetBool(n.value == "1" || n.value == "true")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n.value == "" {
+			return nil
+		}
+
+		i, err := strconv.ParseInt(n.value, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	}
+
+	return nil
+}
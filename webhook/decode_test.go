@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+func TestParsePayloadZeroPaddedIndex(t *testing.T) {
+	payload, err := ParsePayload(url.Values{"leads[add][01][id]": {"5"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(payload.Leads.Add) != 1 || payload.Leads.Add[0].ID != 5 {
+		t.Fatalf("unexpected leads: %+v", payload.Leads.Add)
+	}
+}
+
+func TestParsePayloadDuplicateIndex(t *testing.T) {
+	_, err := ParsePayload(url.Values{
+		"leads[add][1][id]":  {"5"},
+		"leads[add][01][id]": {"6"},
+	})
+	if !errors.Is(err, amocrm.ErrInvalidWebhookPayload) {
+		t.Fatalf("expected ErrInvalidWebhookPayload, got %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package webhook
+
+import (
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+type (
+	// Event is a single entity change, only the field matching the event type is set
+	Event struct {
+		Type     amocrm.WebhookEvent
+		Account  *Account
+		Lead     *Lead
+		Contact  *Contact
+		Company  *Contact
+		Customer *Customer
+		Task     *Task
+		Note     *Note
+		Unsorted *Unsorted
+	}
+
+	Account struct {
+		ID        int    `json:"id"`
+		Subdomain string `json:"subdomain"`
+	}
+
+	Payload struct {
+		Account   *Account        `json:"account"`
+		Leads     *LeadEvents     `json:"leads"`
+		Contacts  *ContactEvents  `json:"contacts"`
+		Companies *ContactEvents  `json:"companies"`
+		Customers *CustomerEvents `json:"customers"`
+		Tasks     *TaskEvents     `json:"task"`
+		Unsorted  *UnsortedEvents `json:"unsorted"`
+	}
+
+	LeadEvents struct {
+		Add         []*Lead         `json:"add"`
+		Update      []*Lead         `json:"update"`
+		Delete      []*Lead         `json:"delete"`
+		Restore     []*Lead         `json:"restore"`
+		Status      []*Lead         `json:"status"`
+		Responsible []*Lead         `json:"responsible"`
+		Note        []*NoteEnvelope `json:"note"`
+	}
+
+	ContactEvents struct {
+		Add         []*Contact      `json:"add"`
+		Update      []*Contact      `json:"update"`
+		Delete      []*Contact      `json:"delete"`
+		Restore     []*Contact      `json:"restore"`
+		Responsible []*Contact      `json:"responsible"`
+		Note        []*NoteEnvelope `json:"note"`
+	}
+
+	CustomerEvents struct {
+		Add         []*Customer     `json:"add"`
+		Update      []*Customer     `json:"update"`
+		Delete      []*Customer     `json:"delete"`
+		Responsible []*Customer     `json:"responsible"`
+		Note        []*NoteEnvelope `json:"note"`
+	}
+
+	TaskEvents struct {
+		Add    []*Task `json:"add"`
+		Update []*Task `json:"update"`
+		Delete []*Task `json:"delete"`
+	}
+
+	UnsortedEvents struct {
+		Add    []*Unsorted `json:"add"`
+		Update []*Unsorted `json:"update"`
+		Delete []*Unsorted `json:"delete"`
+	}
+
+	Lead struct {
+		ID                   int                 `json:"id"`
+		Name                 string              `json:"name"`
+		StatusID             int                 `json:"status_id"`
+		OldStatusID          int                 `json:"old_status_id"`
+		PipelineID           int                 `json:"pipeline_id"`
+		OldPipelineID        int                 `json:"old_pipeline_id"`
+		Price                int                 `json:"price"`
+		ResponsibleUserID    int                 `json:"responsible_user_id"`
+		OldResponsibleUserID int                 `json:"old_responsible_user_id"`
+		CreatedUserID        int                 `json:"created_user_id"`
+		ModifiedUserID       int                 `json:"modified_user_id"`
+		CreatedAt            amocrm.Timestamp    `json:"date_create"`
+		UpdatedAt            amocrm.Timestamp    `json:"last_modified"`
+		AccountID            int                 `json:"account_id"`
+		Tags                 []*amocrm.Tag       `json:"tags"`
+		CustomFields         amocrm.CustomFields `json:"custom_fields"`
+	}
+
+	Contact struct {
+		ID                   int                 `json:"id"`
+		Name                 string              `json:"name"`
+		Type                 string              `json:"type"`
+		CompanyName          string              `json:"company_name"`
+		LinkedCompanyID      int                 `json:"linked_company_id"`
+		ResponsibleUserID    int                 `json:"responsible_user_id"`
+		OldResponsibleUserID int                 `json:"old_responsible_user_id"`
+		CreatedUserID        int                 `json:"created_user_id"`
+		ModifiedUserID       int                 `json:"modified_user_id"`
+		CreatedAt            amocrm.Timestamp    `json:"date_create"`
+		UpdatedAt            amocrm.Timestamp    `json:"last_modified"`
+		AccountID            int                 `json:"account_id"`
+		Tags                 []*amocrm.Tag       `json:"tags"`
+		CustomFields         amocrm.CustomFields `json:"custom_fields"`
+	}
+
+	Customer struct {
+		ID                   int                 `json:"id"`
+		Name                 string              `json:"name"`
+		StatusID             int                 `json:"status_id"`
+		NextPrice            int                 `json:"next_price"`
+		NextDate             amocrm.Timestamp    `json:"next_date"`
+		Periodicity          int                 `json:"periodicity"`
+		ResponsibleUserID    int                 `json:"responsible_user_id"`
+		OldResponsibleUserID int                 `json:"old_responsible_user_id"`
+		CreatedUserID        int                 `json:"created_user_id"`
+		ModifiedUserID       int                 `json:"modified_user_id"`
+		CreatedAt            amocrm.Timestamp    `json:"date_create"`
+		UpdatedAt            amocrm.Timestamp    `json:"last_modified"`
+		AccountID            int                 `json:"account_id"`
+		Tags                 []*amocrm.Tag       `json:"tags"`
+		CustomFields         amocrm.CustomFields `json:"custom_fields"`
+	}
+
+	Task struct {
+		ID                int              `json:"id"`
+		ElementID         int              `json:"element_id"`
+		ElementType       int              `json:"element_type"`
+		TaskType          int              `json:"task_type"`
+		Text              string           `json:"text"`
+		Status            int              `json:"status"`
+		CompleteTill      amocrm.Timestamp `json:"complete_till"`
+		ResponsibleUserID int              `json:"responsible_user_id"`
+		CreatedUserID     int              `json:"created_user_id"`
+		ModifiedUserID    int              `json:"modified_user_id"`
+		CreatedAt         amocrm.Timestamp `json:"date_create"`
+		UpdatedAt         amocrm.Timestamp `json:"last_modified"`
+		AccountID         int              `json:"account_id"`
+	}
+
+	NoteEnvelope struct {
+		Note *Note `json:"note"`
+	}
+
+	Note struct {
+		ID                int              `json:"id"`
+		ElementID         int              `json:"element_id"`
+		ElementType       int              `json:"element_type"`
+		NoteType          amocrm.NoteKind  `json:"note_type"`
+		Text              string           `json:"text"`
+		ResponsibleUserID int              `json:"responsible_user_id"`
+		CreatedUserID     int              `json:"created_by"`
+		ModifiedUserID    int              `json:"modified_by"`
+		CreatedAt         amocrm.Timestamp `json:"date_create"`
+		UpdatedAt         amocrm.Timestamp `json:"last_modified"`
+		AccountID         int              `json:"account_id"`
+	}
+
+	Unsorted struct {
+		UID        string           `json:"uid"`
+		Source     string           `json:"source"`
+		SourceUID  string           `json:"source_uid"`
+		Category   string           `json:"category"`
+		PipelineID int              `json:"pipeline_id"`
+		CreatedAt  amocrm.Timestamp `json:"date_create"`
+		Action     string           `json:"action"`
+		AccountID  int              `json:"account_id"`
+	}
+)
+
+const (
+	companyContactType = "company"
+)
+
+// Events flattens the payload into a list of events in the order amoCRM sends them
+func (p *Payload) Events() []*Event {
+	var events []*Event
+
+	if p.Leads != nil {
+		for _, group := range []struct {
+			t     amocrm.WebhookEvent
+			leads []*Lead
+		}{
+			{amocrm.AddLeadWebhookEvent, p.Leads.Add},
+			{amocrm.UpdateLeadWebhookEvent, p.Leads.Update},
+			{amocrm.DeleteLeadWebhookEvent, p.Leads.Delete},
+			{amocrm.RestoreLeadWebhookEvent, p.Leads.Restore},
+			{amocrm.StatusLeadWebhookEvent, p.Leads.Status},
+			{amocrm.ResponsibleLeadWebhookEvent, p.Leads.Responsible},
+		} {
+			for _, lead := range group.leads {
+				events = append(events, &Event{Type: group.t, Account: p.Account, Lead: lead})
+			}
+		}
+
+		events = append(events, p.noteEvents(amocrm.NoteLeadWebhookEvent, p.Leads.Note)...)
+	}
+
+	events = append(events, p.contactEvents(p.Contacts)...)
+	events = append(events, p.contactEvents(p.Companies)...)
+
+	if p.Customers != nil {
+		for _, group := range []struct {
+			t         amocrm.WebhookEvent
+			customers []*Customer
+		}{
+			{amocrm.AddCustomerWebhookEvent, p.Customers.Add},
+			{amocrm.UpdateCustomerWebhookEvent, p.Customers.Update},
+			{amocrm.DeleteCustomerWebhookEvent, p.Customers.Delete},
+			{amocrm.ResponsibleCustomerWebhookEvent, p.Customers.Responsible},
+		} {
+			for _, customer := range group.customers {
+				events = append(events, &Event{Type: group.t, Account: p.Account, Customer: customer})
+			}
+		}
+
+		events = append(events, p.noteEvents(amocrm.NoteCustomerWebhookEvent, p.Customers.Note)...)
+	}
+
+	if p.Tasks != nil {
+		for _, group := range []struct {
+			t     amocrm.WebhookEvent
+			tasks []*Task
+		}{
+			{amocrm.AddTaskWebhookEvent, p.Tasks.Add},
+			{amocrm.UpdateTaskWebhookEvent, p.Tasks.Update},
+			{amocrm.DeleteTaskWebhookEvent, p.Tasks.Delete},
+		} {
+			for _, task := range group.tasks {
+				events = append(events, &Event{Type: group.t, Account: p.Account, Task: task})
+			}
+		}
+	}
+
+	if p.Unsorted != nil {
+		for _, group := range []struct {
+			t        amocrm.WebhookEvent
+			unsorted []*Unsorted
+		}{
+			{amocrm.AddUnsortedWebhookEvent, p.Unsorted.Add},
+			{amocrm.UpdateUnsortedWebhookEvent, p.Unsorted.Update},
+			{amocrm.DeleteUnsortedWebhookEvent, p.Unsorted.Delete},
+		} {
+			for _, unsorted := range group.unsorted {
+				events = append(events, &Event{Type: group.t, Account: p.Account, Unsorted: unsorted})
+			}
+		}
+	}
+
+	return events
+}
+
+// contactEvents splits contacts and companies, amoCRM sends companies among contacts with the company type
+func (p *Payload) contactEvents(contacts *ContactEvents) []*Event {
+	if contacts == nil {
+		return nil
+	}
+
+	var events []*Event
+	for _, group := range []struct {
+		contactType amocrm.WebhookEvent
+		companyType amocrm.WebhookEvent
+		contacts    []*Contact
+	}{
+		{amocrm.AddContactWebhookEvent, amocrm.AddCompanyWebhookEvent, contacts.Add},
+		{amocrm.UpdateContactWebhookEvent, amocrm.UpdateCompanyWebhookEvent, contacts.Update},
+		{amocrm.DeleteContactWebhookEvent, amocrm.DeleteCompanyWebhookEvent, contacts.Delete},
+		{amocrm.RestoreContactWebhookEvent, amocrm.RestoreCompanyWebhookEvent, contacts.Restore},
+		{amocrm.ResponsibleContactWebhookEvent, amocrm.ResponsibleCompanyWebhookEvent, contacts.Responsible},
+	} {
+		for _, contact := range group.contacts {
+			if contact.Type == companyContactType {
+				events = append(events, &Event{Type: group.companyType, Account: p.Account, Company: contact})
+			} else {
+				events = append(events, &Event{Type: group.contactType, Account: p.Account, Contact: contact})
+			}
+		}
+	}
+
+	for _, envelope := range contacts.Note {
+		if envelope.Note == nil {
+			continue
+		}
+
+		t := amocrm.NoteContactWebhookEvent
+		if envelope.Note.ElementType == int(amocrm.CompanyTaskElementType) {
+			t = amocrm.NoteCompanyWebhookEvent
+		}
+
+		events = append(events, &Event{Type: t, Account: p.Account, Note: envelope.Note})
+	}
+
+	return events
+}
+
+func (p *Payload) noteEvents(t amocrm.WebhookEvent, envelopes []*NoteEnvelope) []*Event {
+	events := make([]*Event, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		if envelope.Note != nil {
+			events = append(events, &Event{Type: t, Account: p.Account, Note: envelope.Note})
+		}
+	}
+
+	return events
+}
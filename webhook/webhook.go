@@ -0,0 +1,200 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+type (
+	HandlerFunc func(ctx context.Context, event *Event) error
+
+	ErrorHandlerFunc func(r *http.Request, err error)
+
+	Option func(h *Handler)
+
+	// Handler receives amoCRM webhooks and dispatches events to the registered callbacks.
+	// Callbacks are called sequentially, a callback error responds with 500, so amoCRM delivers the webhook again.
+	Handler struct {
+		subdomain    string
+		maxBodySize  int64
+		handlers     map[amocrm.WebhookEvent][]HandlerFunc
+		errorHandler ErrorHandlerFunc
+		mu           sync.RWMutex
+	}
+)
+
+const (
+	defaultMaxBodySize = 10 << 20
+)
+
+// NewHandler creates webhook handler, requests from an account with another subdomain are rejected with 403.
+// Empty subdomain disables the check.
+func NewHandler(subdomain string, opts ...Option) *Handler {
+	h := &Handler{
+		subdomain:   subdomain,
+		maxBodySize: defaultMaxBodySize,
+		handlers:    make(map[amocrm.WebhookEvent][]HandlerFunc),
+	}
+
+	for _, o := range opts {
+		o(h)
+	}
+
+	return h
+}
+
+func WithErrorHandler(fn ErrorHandlerFunc) Option {
+	return func(h *Handler) {
+		h.errorHandler = fn
+	}
+}
+
+func WithMaxBodySize(n int64) Option {
+	return func(h *Handler) {
+		h.maxBodySize = n
+	}
+}
+
+func (h *Handler) Handle(eventType amocrm.WebhookEvent, fn HandlerFunc) error {
+	if !eventType.IsValid() {
+		return amocrm.ErrInvalidEventType
+	}
+
+	h.mu.Lock()
+	h.handlers[eventType] = append(h.handlers[eventType], fn)
+	h.mu.Unlock()
+
+	return nil
+}
+
+func (h *Handler) OnLead(eventType amocrm.WebhookEvent, fn func(ctx context.Context, account *Account, lead *Lead) error) error {
+	if !strings.HasSuffix(string(eventType), "_lead") || eventType == amocrm.NoteLeadWebhookEvent {
+		return amocrm.ErrInvalidEventType
+	}
+
+	return h.Handle(eventType, func(ctx context.Context, event *Event) error {
+		return fn(ctx, event.Account, event.Lead)
+	})
+}
+
+func (h *Handler) OnContact(eventType amocrm.WebhookEvent, fn func(ctx context.Context, account *Account, contact *Contact) error) error {
+	if !strings.HasSuffix(string(eventType), "_contact") || eventType == amocrm.NoteContactWebhookEvent {
+		return amocrm.ErrInvalidEventType
+	}
+
+	return h.Handle(eventType, func(ctx context.Context, event *Event) error {
+		return fn(ctx, event.Account, event.Contact)
+	})
+}
+
+func (h *Handler) OnCompany(eventType amocrm.WebhookEvent, fn func(ctx context.Context, account *Account, company *Contact) error) error {
+	if !strings.HasSuffix(string(eventType), "_company") || eventType == amocrm.NoteCompanyWebhookEvent {
+		return amocrm.ErrInvalidEventType
+	}
+
+	return h.Handle(eventType, func(ctx context.Context, event *Event) error {
+		return fn(ctx, event.Account, event.Company)
+	})
+}
+
+func (h *Handler) OnCustomer(eventType amocrm.WebhookEvent, fn func(ctx context.Context, account *Account, customer *Customer) error) error {
+	if !strings.HasSuffix(string(eventType), "_customer") || eventType == amocrm.NoteCustomerWebhookEvent {
+		return amocrm.ErrInvalidEventType
+	}
+
+	return h.Handle(eventType, func(ctx context.Context, event *Event) error {
+		return fn(ctx, event.Account, event.Customer)
+	})
+}
+
+func (h *Handler) OnTask(eventType amocrm.WebhookEvent, fn func(ctx context.Context, account *Account, task *Task) error) error {
+	if !strings.HasSuffix(string(eventType), "_task") {
+		return amocrm.ErrInvalidEventType
+	}
+
+	return h.Handle(eventType, func(ctx context.Context, event *Event) error {
+		return fn(ctx, event.Account, event.Task)
+	})
+}
+
+func (h *Handler) OnNote(eventType amocrm.WebhookEvent, fn func(ctx context.Context, account *Account, note *Note) error) error {
+	if !strings.HasPrefix(string(eventType), "note_") {
+		return amocrm.ErrInvalidEventType
+	}
+
+	return h.Handle(eventType, func(ctx context.Context, event *Event) error {
+		return fn(ctx, event.Account, event.Note)
+	})
+}
+
+func (h *Handler) OnUnsorted(eventType amocrm.WebhookEvent, fn func(ctx context.Context, account *Account, unsorted *Unsorted) error) error {
+	if !strings.HasSuffix(string(eventType), "_unsorted") {
+		return amocrm.ErrInvalidEventType
+	}
+
+	return h.Handle(eventType, func(ctx context.Context, event *Event) error {
+		return fn(ctx, event.Account, event.Unsorted)
+	})
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	if err := r.ParseForm(); err != nil {
+		h.fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	payload, err := ParsePayload(r.PostForm)
+	if err != nil {
+		h.fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if h.subdomain != "" && (payload.Account == nil || !strings.EqualFold(payload.Account.Subdomain, h.subdomain)) {
+		h.fail(w, r, amocrm.ErrUnauthorized, http.StatusForbidden)
+		return
+	}
+
+	if err := h.Dispatch(r.Context(), payload); err != nil {
+		h.fail(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Dispatch calls registered callbacks for every event of the payload and stops at the first error
+func (h *Handler) Dispatch(ctx context.Context, payload *Payload) error {
+	for _, event := range payload.Events() {
+		// callbacks are called without the lock, so they are able to register other callbacks
+		h.mu.RLock()
+		handlers := append([]HandlerFunc(nil), h.handlers[event.Type]...)
+		h.mu.RUnlock()
+
+		for _, fn := range handlers {
+			if err := fn(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, err error, statusCode int) {
+	if h.errorHandler != nil {
+		h.errorHandler(r, err)
+	}
+
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
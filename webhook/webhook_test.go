@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	amocrm "github.com/ogi4i/amocrm-client"
+)
+
+func TestDispatchCustomerEvents(t *testing.T) {
+	payload, err := ParsePayload(url.Values{
+		"account[subdomain]":           {"example"},
+		"customers[update][0][id]":     {"7"},
+		"customers[note][0][note][id]": {"9"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var customerID, noteID int
+	h := NewHandler("example")
+	if err := h.OnCustomer(amocrm.UpdateCustomerWebhookEvent, func(ctx context.Context, account *Account, customer *Customer) error {
+		customerID = customer.ID
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.OnNote(amocrm.NoteCustomerWebhookEvent, func(ctx context.Context, account *Account, note *Note) error {
+		noteID = note.ID
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Dispatch(context.Background(), payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if customerID != 7 || noteID != 9 {
+		t.Fatalf("unexpected dispatch: customer %d, note %d", customerID, noteID)
+	}
+}
+
+func TestHandleInvalidEvent(t *testing.T) {
+	h := NewHandler("")
+	if err := h.Handle("status_customer", func(ctx context.Context, event *Event) error { return nil }); err != amocrm.ErrInvalidEventType {
+		t.Fatalf("expected ErrInvalidEventType, got %v", err)
+	}
+}
+
+func TestDispatchRegisterFromCallback(t *testing.T) {
+	payload, err := ParsePayload(url.Values{"leads[add][0][id]": {"1"}, "leads[update][0][id]": {"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := false
+	h := NewHandler("")
+	if err := h.OnLead(amocrm.AddLeadWebhookEvent, func(ctx context.Context, account *Account, lead *Lead) error {
+		return h.OnLead(amocrm.UpdateLeadWebhookEvent, func(ctx context.Context, account *Account, lead *Lead) error {
+			updated = true
+			return nil
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- h.Dispatch(context.Background(), payload) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("dispatch deadlocked")
+	}
+
+	if !updated {
+		t.Fatal("handler registered by a callback is not called")
+	}
+}